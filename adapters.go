@@ -20,6 +20,25 @@ type (
 		SupportsLimitOnUpdate() bool
 		//Returns true if the dialect supports RETURN expressions
 		SupportsReturn() bool
+		//Returns true if the dialect supports SELECT EXISTS(...) statements without a FROM clause, used by Dataset#Exists
+		SupportsSelectExists() bool
+		//Returns true if the dialect sorts NULLs before other values in ascending order and after them in descending order
+		//when no NULLS FIRST/LAST is given (e.g. mysql and sqlite3), used by Dataset#SeekAfter
+		SortsNullsFirst() bool
+		//Returns true if the dialect supports a conflict target (e.g. ON CONFLICT ("id")), used by Dataset#OnConflict
+		SupportsConflictTarget() bool
+		//Returns true if the dialect supports leaving a conflicting row as is (e.g. ON CONFLICT DO NOTHING), used by Dataset#OnConflict
+		SupportsConflictDoNothing() bool
+		//Returns true if the dialect supports a WHERE clause when updating a conflicting row, used by Dataset#OnConflict
+		SupportsConflictUpdateWhere() bool
+		//Returns true if the dialect can update a conflicting row without a conflict target (e.g. mysql), used by
+		//Dataset#OnConflict
+		SupportsConflictUpdateWithoutTarget() bool
+		//Returns true if the dialect supports the InsertVariant (e.g. INSERT OR IGNORE), used by Dataset#InsertAs
+		SupportsInsertVariant(variant InsertVariant) bool
+		//Returns true if the dialect supports array values (e.g. ARRAY['a', 'b']), slice fields of a struct are only inserted
+		//and updated as arrays if it does
+		SupportsArrays() bool
 		//Generates the sql for placeholders. Only invoked when not interpolating values.
 		//
 		//buf: The current SqlBuilder to write the sql to
@@ -33,6 +52,12 @@ type (
 		//
 		//buf: The current SqlBuilder to write the sql to
 		InsertBeginSql(buf *SqlBuilder) error
+		//Generates the correct beginning sql for an INSERT statement with an InsertVariant other than INSERT_DEFAULT
+		//(e.g. INSERT OR IGNORE INTO)
+		//
+		//buf: The current SqlBuilder to write the sql to
+		//variant: The InsertVariant of the statement
+		InsertVariantBeginSql(buf *SqlBuilder, variant InsertVariant) error
 		//Generates the correct beginning sql for a DELETE statement
 		//
 		//buf: The current SqlBuilder to write the sql to
@@ -133,6 +158,10 @@ type (
 		//
 		//buf: The current SqlBuilder to write the sql to
 		LiteralString(buf *SqlBuilder, s string) error
+		//Generates SQL value for the JSON encoded value of a struct field tagged with goqu:"json"
+		//
+		//buf: The current SqlBuilder to write the sql to
+		LiteralJson(buf *SqlBuilder, json []byte) error
 		//Generates SQL value for a Slice
 		//
 		//buf: The current SqlBuilder to write the sql to
@@ -161,6 +190,14 @@ type (
 		//
 		//buf: The current SqlBuilder to write the sql to
 		CastExpressionSql(buf *SqlBuilder, casted CastExpression) error
+		//Generates SQL value for an ArrayExpression
+		//
+		//buf: The current SqlBuilder to write the sql to
+		ArrayExpressionSql(buf *SqlBuilder, array ArrayExpression) error
+		//Generates SQL value for an ExcludedExpression
+		//
+		//buf: The current SqlBuilder to write the sql to
+		ExcludedExpressionSql(buf *SqlBuilder, excluded ExcludedExpression) error
 		//Generates SQL value for an CompoundExpression
 		//
 		//buf: The current SqlBuilder to write the sql to
@@ -194,6 +231,49 @@ type (
 		//
 		//buf: The current SqlBuilder to write the sql to
 		InsertValuesSql(buf *SqlBuilder, values [][]interface{}) error
+		//Generates the sql to begin handling conflicts in an INSERT statement (e.g. ON CONFLICT ("id"))
+		//
+		//buf: The current SqlBuilder to write the sql to
+		//target: The conflict target, nil if there is none
+		OnConflictSql(buf *SqlBuilder, target Expression) error
+		//Generates the sql to leave a conflicting row as is (e.g. DO NOTHING)
+		//
+		//buf: The current SqlBuilder to write the sql to
+		ConflictDoNothingSql(buf *SqlBuilder) error
+		//Generates the sql to update a conflicting row (e.g. DO UPDATE SET "name"=EXCLUDED."name")
+		//
+		//buf: The current SqlBuilder to write the sql to
+		//updates: The updates to apply to the conflicting row
+		//where: The condition the conflicting row must match to be updated, nil if there is none
+		ConflictDoUpdateSql(buf *SqlBuilder, updates []UpdateExpression, where ExpressionList) error
+		//Generates the sql to create a SAVEPOINT within a transaction
+		//
+		//buf: The current SqlBuilder to write the sql to
+		//name: The name of the savepoint
+		SavepointSql(buf *SqlBuilder, name string) error
+		//Generates the sql to ROLLBACK a transaction to a SAVEPOINT
+		//
+		//buf: The current SqlBuilder to write the sql to
+		//name: The name of the savepoint
+		RollbackToSavepointSql(buf *SqlBuilder, name string) error
+		//Generates the sql to RELEASE a SAVEPOINT
+		//
+		//buf: The current SqlBuilder to write the sql to
+		//name: The name of the savepoint
+		ReleaseSavepointSql(buf *SqlBuilder, name string) error
+		//Returns true if the error returned by the driver is a serialization failure or deadlock, meaning the transaction can safely be retried.
+		//Used by Database#WithTx
+		//
+		//err: The error returned by the driver
+		IsRetryableTxError(err error) bool
+		//Returns the category of an error returned by the driver (e.g. ErrUniqueViolation, ErrDeadlock), nil if the error does
+		//not belong to a category. Used to classify the errors returned by a Database or TxDatabase, see DatabaseError
+		//
+		//err: The error returned by the driver
+		ClassifyError(err error) error
+		//Returns the maximum number of placeholders the dialect supports in a single statement, 0 if there is no limit.
+		//Used to split inserts into chunks, see Dataset#ChunkInserts
+		PlaceholderLimit() int
 	}
 )
 
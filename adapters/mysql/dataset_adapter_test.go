@@ -1,10 +1,12 @@
 package mysql
 
 import (
+	"fmt"
 	"regexp"
 	"testing"
 
 	"github.com/doug-martin/goqu"
+	driver "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -71,6 +73,80 @@ func (me *datasetAdapterTest) TestIdentifiers() {
 	assert.Equal(t, sql, "SELECT `a`, `a`.`b`.`c`, `c`.`d`, `test` AS `test` FROM `test`")
 }
 
+func (me *datasetAdapterTest) TestArrayOperators() {
+	t := me.T()
+	_, _, err := me.GetDs("test").Where(goqu.I("tags").Contains([]string{"a"})).ToSql()
+	assert.EqualError(t, err, "goqu: Boolean operator 18 not supported")
+}
+
+func (me *datasetAdapterTest) TestSliceFields() {
+	t := me.T()
+	type item struct {
+		Name string   `db:"name"`
+		Tags []string `db:"tags"`
+	}
+	sql, _, err := me.GetDs("test").ToInsertSql(item{Name: "Test", Tags: []string{"a", "b"}})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "INSERT INTO `test` (`name`, `tags`) VALUES ('Test', ('a', 'b'))")
+}
+
+func (me *datasetAdapterTest) TestOnConflict() {
+	t := me.T()
+	ds := me.GetDs("test")
+	sql, _, err := ds.OnConflict(nil, goqu.DoUpdate(goqu.Record{"name": goqu.Excluded("name"), "count": goqu.L("`count` + 1")})).
+		ToInsertSql(goqu.Record{"id": 1, "name": "Test"})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "INSERT INTO `test` (`id`, `name`) VALUES (1, 'Test') ON DUPLICATE KEY UPDATE `count`=`count` + 1,`name`=VALUES(`name`)")
+
+	_, _, err = ds.OnConflict("id", goqu.DoUpdate(goqu.Record{"name": "Test"})).ToInsertSql(goqu.Record{"id": 1})
+	assert.EqualError(t, err, "goqu: Adapter does not support a conflict target")
+	_, _, err = ds.OnConflict(nil, goqu.DoNothing()).ToInsertSql(goqu.Record{"id": 1})
+	assert.EqualError(t, err, "goqu: Adapter does not support DO NOTHING on conflict")
+	_, _, err = ds.OnConflict(nil, goqu.DoUpdate(goqu.Record{"name": "Test"}, goqu.I("id").Gt(1))).ToInsertSql(goqu.Record{"id": 1})
+	assert.EqualError(t, err, "goqu: Adapter does not support conditions when updating on conflict")
+}
+
+func (me *datasetAdapterTest) TestSeekAfter() {
+	t := me.T()
+	cursor, err := goqu.EncodeCursor("Test1", int64(10))
+	assert.NoError(t, err)
+	seek, err := me.GetDs("test").Order(goqu.I("name").Asc(), goqu.I("id").Asc()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, _, err := seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "SELECT * FROM `test` WHERE (`name`, `id`) > ('Test1', 10) ORDER BY `name` ASC, `id` ASC")
+}
+
+func (me *datasetAdapterTest) TestInsertVariants() {
+	t := me.T()
+	ds := me.GetDs("test")
+	item := goqu.Record{"id": 1}
+	sql, _, err := ds.InsertIgnore().ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "INSERT IGNORE INTO `test` (`id`) VALUES (1)")
+
+	sql, _, err = ds.Replace().ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "REPLACE INTO `test` (`id`) VALUES (1)")
+
+	_, _, err = ds.InsertAs(goqu.INSERT_ABORT).ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: Adapter does not support INSERT ABORT")
+	_, _, err = ds.InsertAs(goqu.INSERT_FAIL).ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: Adapter does not support INSERT FAIL")
+	_, _, err = ds.InsertAs(goqu.INSERT_ROLLBACK).ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: Adapter does not support INSERT ROLLBACK")
+}
+
+func (me *datasetAdapterTest) TestLiteralJson() {
+	t := me.T()
+	type item struct {
+		Attrs map[string]string `db:"attrs" goqu:"json"`
+	}
+	sql, _, err := me.GetDs("test").ToInsertSql(item{Attrs: map[string]string{"a": `b"c`}})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "INSERT INTO `test` (`attrs`) VALUES ('{\\\"a\\\":\\\"b\\\\\\\"c\\\"}')")
+}
+
 func (me *datasetAdapterTest) TestLiteralString() {
 	t := me.T()
 	ds := me.GetDs("test")
@@ -178,6 +254,43 @@ func (me *datasetAdapterTest) TestBooleanOperations() {
 
 }
 
+func (me *datasetAdapterTest) TestSavepointSql() {
+	t := me.T()
+	dsAdapter := me.GetDs("test").Adapter()
+	buf := goqu.NewSqlBuilder(false)
+	assert.NoError(t, dsAdapter.SavepointSql(buf, "sp1"))
+	assert.Equal(t, buf.String(), "SAVEPOINT `sp1`")
+	buf = goqu.NewSqlBuilder(false)
+	assert.NoError(t, dsAdapter.RollbackToSavepointSql(buf, "sp1"))
+	assert.Equal(t, buf.String(), "ROLLBACK TO SAVEPOINT `sp1`")
+	buf = goqu.NewSqlBuilder(false)
+	assert.NoError(t, dsAdapter.ReleaseSavepointSql(buf, "sp1"))
+	assert.Equal(t, buf.String(), "RELEASE SAVEPOINT `sp1`")
+}
+
+func (me *datasetAdapterTest) TestIsRetryableTxError() {
+	t := me.T()
+	dsAdapter := me.GetDs("test").Adapter()
+	assert.True(t, dsAdapter.IsRetryableTxError(&driver.MySQLError{Number: 1213}))
+	assert.True(t, dsAdapter.IsRetryableTxError(fmt.Errorf("wrapped: %w", &driver.MySQLError{Number: 1213})))
+	assert.False(t, dsAdapter.IsRetryableTxError(&driver.MySQLError{Number: 1062}))
+	assert.False(t, dsAdapter.IsRetryableTxError(fmt.Errorf("Deadlock found when trying to get lock")))
+	assert.False(t, dsAdapter.IsRetryableTxError(nil))
+}
+
+func (me *datasetAdapterTest) TestClassifyError() {
+	t := me.T()
+	dsAdapter := me.GetDs("test").Adapter()
+	assert.Equal(t, dsAdapter.ClassifyError(&driver.MySQLError{Number: 1062}), goqu.ErrUniqueViolation)
+	assert.Equal(t, dsAdapter.ClassifyError(&driver.MySQLError{Number: 1452}), goqu.ErrForeignKeyViolation)
+	assert.Equal(t, dsAdapter.ClassifyError(&driver.MySQLError{Number: 1048}), goqu.ErrNotNullViolation)
+	assert.Equal(t, dsAdapter.ClassifyError(&driver.MySQLError{Number: 1213}), goqu.ErrDeadlock)
+	assert.Equal(t, dsAdapter.ClassifyError(&driver.MySQLError{Number: 2006}), goqu.ErrConnectionLost)
+	assert.Equal(t, dsAdapter.ClassifyError(fmt.Errorf("wrapped: %w", &driver.MySQLError{Number: 1062})), goqu.ErrUniqueViolation)
+	assert.Nil(t, dsAdapter.ClassifyError(&driver.MySQLError{Number: 1064}))
+	assert.Nil(t, dsAdapter.ClassifyError(fmt.Errorf("Duplicate entry")))
+}
+
 func TestDatasetAdapterSuite(t *testing.T) {
 	suite.Run(t, new(datasetAdapterTest))
 }
@@ -1,6 +1,9 @@
 package mysql
 
 import (
+    "errors"
+    "reflect"
+
     "github.com/doug-martin/goqu"
 )
 
@@ -9,9 +12,12 @@ var (
     quote_rune          = '`'
     singlq_quote        = '\''
     default_values_frag = []byte("")
+    on_conflict_frag    = []byte(" ON DUPLICATE KEY")
+    do_update_frag      = []byte(" UPDATE ")
     mysql_true          = []byte("1")
     mysql_false         = []byte("0")
     time_format         = "2006-01-02 15:04:05"
+    max_placeholders    = 65535 //mysql uses a uint16 for the number of placeholders in a prepared statement
     operator_lookup     = map[goqu.BooleanOperation][]byte{
         goqu.EQ_OP:                []byte("="),
         goqu.NEQ_OP:               []byte("!="),
@@ -32,8 +38,30 @@ var (
         goqu.REGEXP_I_LIKE_OP:     []byte("REGEXP"),
         goqu.REGEXP_NOT_I_LIKE_OP: []byte("NOT REGEXP"),
     }
+    insert_variant_lookup = map[goqu.InsertVariant][]byte{
+        goqu.INSERT_IGNORE:  []byte("INSERT IGNORE INTO"),
+        goqu.INSERT_REPLACE: []byte("REPLACE INTO"),
+    }
 )
 
+//Error numbers of errors that can be retried (ER_LOCK_DEADLOCK)
+var retryable_error_numbers = map[uint64]bool{
+    1213: true,
+}
+
+//Error numbers of the errors classified by ClassifyError
+var error_number_kinds = map[uint64]error{
+    1062: goqu.ErrUniqueViolation,     //ER_DUP_ENTRY
+    1586: goqu.ErrUniqueViolation,     //ER_DUP_ENTRY_WITH_KEY_NAME
+    1451: goqu.ErrForeignKeyViolation, //ER_ROW_IS_REFERENCED_2
+    1452: goqu.ErrForeignKeyViolation, //ER_NO_REFERENCED_ROW_2
+    1048: goqu.ErrNotNullViolation,    //ER_BAD_NULL_ERROR
+    1213: goqu.ErrDeadlock,            //ER_LOCK_DEADLOCK
+    1053: goqu.ErrConnectionLost,      //ER_SERVER_SHUTDOWN
+    2006: goqu.ErrConnectionLost,      //CR_SERVER_GONE_ERROR
+    2013: goqu.ErrConnectionLost,      //CR_SERVER_LOST
+}
+
 type DatasetAdapter struct {
     *goqu.DefaultAdapter
 }
@@ -58,6 +86,43 @@ func (me *DatasetAdapter) SupportsOrderByOnUpdate() bool {
     return true
 }
 
+//mysql sorts NULLs as if they are smaller than any other value
+func (me *DatasetAdapter) SortsNullsFirst() bool {
+    return true
+}
+
+//mysql does not support a conflict target, a duplicate value in any unique index is a conflict
+func (me *DatasetAdapter) SupportsConflictTarget() bool {
+    return false
+}
+
+func (me *DatasetAdapter) SupportsConflictDoNothing() bool {
+    return false
+}
+
+func (me *DatasetAdapter) SupportsConflictUpdateWhere() bool {
+    return false
+}
+
+func (me *DatasetAdapter) SupportsConflictUpdateWithoutTarget() bool {
+    return true
+}
+
+func (me *DatasetAdapter) SupportsArrays() bool {
+    return false
+}
+
+//Generates the sql for an ExcludedExpression
+//   Excluded("name") -> VALUES(`name`)
+func (me *DatasetAdapter) ExcludedExpressionSql(buf *goqu.SqlBuilder, excluded goqu.ExcludedExpression) error {
+    buf.WriteString("VALUES(")
+    if err := me.Literal(buf, excluded.Col()); err != nil {
+        return err
+    }
+    buf.WriteRune(')')
+    return nil
+}
+
 func (me *DatasetAdapter) LiteralString(buf *goqu.SqlBuilder, s string) error {
     if buf.IsPrepared {
         return me.PlaceHolderSql(buf, s)
@@ -86,16 +151,54 @@ func (me *DatasetAdapter) LiteralString(buf *goqu.SqlBuilder, s string) error {
     return nil
 }
 
+//Returns true if the error is a deadlock
+func (me *DatasetAdapter) IsRetryableTxError(err error) bool {
+    number, ok := errorNumber(err)
+    return ok && retryable_error_numbers[number]
+}
+
+//Classifies a driver error by its error number
+func (me *DatasetAdapter) ClassifyError(err error) error {
+    if number, ok := errorNumber(err); ok {
+        if kind, ok := error_number_kinds[number]; ok {
+            return kind
+        }
+    }
+    return me.DefaultAdapter.ClassifyError(err)
+}
+
+//Returns the error number of a driver error with an unsigned Number field (e.g. mysql.MySQLError). The adapter does not
+//import the driver so the field is read by name, this is best effort and an error whose number cannot be read is not
+//classified. See TestClassifyError for the driver types it is tested against.
+func errorNumber(err error) (uint64, bool) {
+    for ; err != nil; err = errors.Unwrap(err) {
+        val := reflect.Indirect(reflect.ValueOf(err))
+        if val.Kind() == reflect.Struct {
+            if number := val.FieldByName("Number"); number.IsValid() {
+                switch number.Kind() {
+                case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+                    return number.Uint(), true
+                }
+            }
+        }
+    }
+    return 0, false
+}
+
 func newDatasetAdapter(ds *goqu.Dataset) goqu.Adapter {
     def := goqu.NewDefaultAdapter(ds).(*goqu.DefaultAdapter)
     def.PlaceHolderRune = placeholder_rune
     def.IncludePlaceholderNum = false
     def.QuoteRune = quote_rune
     def.DefaultValuesFragment = default_values_frag
+    def.OnConflictFragment = on_conflict_frag
+    def.ConflictDoUpdateFragment = do_update_frag
     def.True = mysql_true
     def.False = mysql_false
     def.TimeFormat = time_format
     def.BooleanOperatorLookup = operator_lookup
+    def.InsertVariantLookup = insert_variant_lookup
+    def.MaxPlaceholders = max_placeholders
     return &DatasetAdapter{def}
 }
 
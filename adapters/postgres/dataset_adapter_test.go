@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"fmt"
 	"github.com/doug-martin/goqu"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"testing"
@@ -24,6 +26,115 @@ func (me *datasetAdapterTest) TestPlaceholderSql() {
 	assert.Equal(t, sql, "$1$2$3$4")
 }
 
+func (me *datasetAdapterTest) TestOnConflict() {
+	t := me.T()
+	ds := goqu.New("postgres", nil).From("items")
+	sql, _, err := ds.OnConflict("id", goqu.DoUpdate(goqu.Record{"name": goqu.Excluded("name")})).
+		Returning("id").
+		ToInsertSql(goqu.Record{"id": 1, "name": "Test"})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("id", "name") VALUES (1, 'Test') ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name" RETURNING "id"`)
+
+	sql, args, err := ds.Prepared(true).
+		OnConflict("id", goqu.DoUpdate(goqu.Record{"name": "Test2"}, goqu.I("items.name").Neq("Test2"))).
+		ToInsertSql(goqu.Record{"id": 1, "name": "Test"})
+	assert.NoError(t, err)
+	assert.Equal(t, args, []interface{}{int64(1), "Test", "Test2", "Test2"})
+	assert.Equal(t, sql, `INSERT INTO "items" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name"=$3 WHERE ("items"."name" != $4)`)
+
+	_, _, err = ds.OnConflict(nil, goqu.DoUpdate(goqu.Record{"name": goqu.Excluded("name")})).
+		ToInsertSql(goqu.Record{"id": 1, "name": "Test"})
+	assert.EqualError(t, err, "goqu: Adapter requires a conflict target when updating on conflict")
+}
+
+func (me *datasetAdapterTest) TestLiteralJson() {
+	t := me.T()
+	type item struct {
+		Name  string            `db:"name"`
+		Attrs map[string]string `db:"attrs" goqu:"json"`
+	}
+	ds := goqu.New("postgres", nil).From("items")
+	i := item{Name: "Test", Attrs: map[string]string{"a": "b's"}}
+	sql, _, err := ds.ToInsertSql(i)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("name", "attrs") VALUES ('Test', '{"a":"b''s"}'::jsonb)`)
+
+	sql, args, err := ds.Prepared(true).ToUpdateSql(i)
+	assert.NoError(t, err)
+	assert.Equal(t, args, []interface{}{"Test", `{"a":"b's"}`})
+	assert.Equal(t, sql, `UPDATE "items" SET "name"=$1,"attrs"=$2::jsonb`)
+
+	i.Attrs = nil
+	sql, _, err = ds.ToUpdateSql(i)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `UPDATE "items" SET "name"='Test',"attrs"=NULL`)
+}
+
+func (me *datasetAdapterTest) TestArrayExpressionSql() {
+	t := me.T()
+	ds := goqu.New("postgres", nil).From("items")
+	sql, _, err := ds.Where(goqu.I("tags").Contains([]string{"a", "b's"})).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE ("tags" @> ARRAY['a', 'b''s'])`)
+
+	sql, _, err = ds.Where(goqu.I("tags").Overlaps([]string{})).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE ("tags" && '{}')`)
+
+	sql, args, err := ds.Prepared(true).Where(goqu.I("id").EqAny([]int64{1, 2, 3}), goqu.I("tags").ContainedBy([]string{`a"b`})).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, args, []interface{}{"{1,2,3}", `{"a\"b"}`})
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE (("id" = ANY($1)) AND ("tags" <@ $2))`)
+
+	type item struct {
+		Tags []string `db:"tags"`
+	}
+	sql, args, err = ds.Prepared(true).ToInsertSql(item{Tags: []string{"a", "b"}}, item{})
+	assert.NoError(t, err)
+	assert.Equal(t, args, []interface{}{`{"a","b"}`, nil})
+	assert.Equal(t, sql, `INSERT INTO "items" ("tags") VALUES ($1), ($2)`)
+}
+
+func (me *datasetAdapterTest) TestIsRetryableTxError() {
+	t := me.T()
+	dsAdapter := newDatasetAdapter(goqu.From("test"))
+	assert.True(t, dsAdapter.IsRetryableTxError(&pq.Error{Code: "40001"}))
+	assert.True(t, dsAdapter.IsRetryableTxError(&pq.Error{Code: "40P01"}))
+	assert.True(t, dsAdapter.IsRetryableTxError(fmt.Errorf("wrapped: %w", &pq.Error{Code: "40001"})))
+	assert.False(t, dsAdapter.IsRetryableTxError(&pq.Error{Code: "23505"}))
+	assert.False(t, dsAdapter.IsRetryableTxError(fmt.Errorf("could not serialize access")))
+	assert.False(t, dsAdapter.IsRetryableTxError(nil))
+}
+
+//an error of a driver that reports its SQLSTATE code with a method (e.g. pgx)
+type sqlStateError struct {
+	code string
+}
+
+func (me sqlStateError) Error() string {
+	return "sql state " + me.code
+}
+
+func (me sqlStateError) SQLState() string {
+	return me.code
+}
+
+func (me *datasetAdapterTest) TestClassifyError() {
+	t := me.T()
+	dsAdapter := newDatasetAdapter(goqu.From("test"))
+	assert.Equal(t, dsAdapter.ClassifyError(&pq.Error{Code: "23505"}), goqu.ErrUniqueViolation)
+	assert.Equal(t, dsAdapter.ClassifyError(&pq.Error{Code: "23503"}), goqu.ErrForeignKeyViolation)
+	assert.Equal(t, dsAdapter.ClassifyError(&pq.Error{Code: "23502"}), goqu.ErrNotNullViolation)
+	assert.Equal(t, dsAdapter.ClassifyError(&pq.Error{Code: "40P01"}), goqu.ErrDeadlock)
+	assert.Equal(t, dsAdapter.ClassifyError(&pq.Error{Code: "40001"}), goqu.ErrSerializationFailure)
+	assert.Equal(t, dsAdapter.ClassifyError(&pq.Error{Code: "08006"}), goqu.ErrConnectionLost)
+	assert.Equal(t, dsAdapter.ClassifyError(fmt.Errorf("wrapped: %w", &pq.Error{Code: "23505"})), goqu.ErrUniqueViolation)
+	assert.Equal(t, dsAdapter.ClassifyError(sqlStateError{code: "23505"}), goqu.ErrUniqueViolation)
+	assert.Equal(t, dsAdapter.ClassifyError(fmt.Errorf("wrapped: %w", sqlStateError{code: "40001"})), goqu.ErrSerializationFailure)
+	assert.Nil(t, dsAdapter.ClassifyError(&pq.Error{Code: "42601"}))
+	assert.Nil(t, dsAdapter.ClassifyError(fmt.Errorf("duplicate key value")))
+}
+
 func TestDatasetAdapterSuite(t *testing.T) {
 	suite.Run(t, new(datasetAdapterTest))
 }
@@ -1,16 +1,114 @@
 package postgres
 
 import (
+	"errors"
+	"reflect"
+	"strings"
+
 	"github.com/doug-martin/goqu"
 )
 
-const placeholder_rune = '$'
+const (
+	placeholder_rune = '$'
+	//jsonb can be assigned to json, jsonb and text columns
+	json_cast = "::jsonb"
+	//the type of an empty array literal is inferred from where it is used
+	empty_array = "'{}'"
+	//postgres uses a uint16 for the number of bind parameters
+	max_placeholders = 65535
+)
+
+//SQLSTATE codes of errors that can be retried (serialization_failure, deadlock_detected)
+var retryable_error_codes = map[string]bool{
+	"40001": true,
+	"40P01": true,
+}
+
+//SQLSTATE codes of the errors classified by ClassifyError, codes of class 08 (connection_exception) are classified as
+//goqu.ErrConnectionLost
+var error_code_kinds = map[string]error{
+	"23505": goqu.ErrUniqueViolation,
+	"23503": goqu.ErrForeignKeyViolation,
+	"23502": goqu.ErrNotNullViolation,
+	"40P01": goqu.ErrDeadlock,
+	"40001": goqu.ErrSerializationFailure,
+	"57P01": goqu.ErrConnectionLost,
+}
+
+type DatasetAdapter struct {
+	*goqu.DefaultAdapter
+}
+
+//Generates SQL for a JSON encoded value cast to jsonb, so the value is not treated as text when the type of the column
+//cannot be inferred (e.g. INSERT ... SELECT)
+func (me *DatasetAdapter) LiteralJson(buf *goqu.SqlBuilder, json []byte) error {
+	if err := me.DefaultAdapter.LiteralJson(buf, json); err != nil {
+		return err
+	}
+	buf.WriteString(json_cast)
+	return nil
+}
+
+//Generates SQL for an ArrayExpression. When prepared the array is bound as a single argument in the postgres array format
+//(e.g. {"a","b"}), an empty array is written as '{}' as the type of an empty ARRAY[] cannot be determined.
+func (me *DatasetAdapter) ArrayExpressionSql(buf *goqu.SqlBuilder, array goqu.ArrayExpression) error {
+	if buf.IsPrepared {
+		value, err := array.Value()
+		if err != nil {
+			return err
+		}
+		return me.PlaceHolderSql(buf, value)
+	}
+	if len(array.Values()) == 0 {
+		buf.WriteString(empty_array)
+		return nil
+	}
+	return me.DefaultAdapter.ArrayExpressionSql(buf, array)
+}
+
+//Returns true if the error is a serialization failure or deadlock
+func (me *DatasetAdapter) IsRetryableTxError(err error) bool {
+	code, ok := errorCode(err)
+	return ok && retryable_error_codes[code]
+}
+
+//Classifies a driver error by its SQLSTATE code
+func (me *DatasetAdapter) ClassifyError(err error) error {
+	if code, ok := errorCode(err); ok {
+		if kind, ok := error_code_kinds[code]; ok {
+			return kind
+		}
+		if strings.HasPrefix(code, "08") {
+			return goqu.ErrConnectionLost
+		}
+	}
+	return me.DefaultAdapter.ClassifyError(err)
+}
+
+//Returns the SQLSTATE code of a driver error. Supports errors implementing SQLState() string (e.g. pgx and pq.Error),
+//errors of older drivers are read from a string Code field by name as a best effort fallback. See TestClassifyError for
+//the driver types it is tested against.
+func errorCode(err error) (string, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(interface{ SQLState() string }); ok {
+			return e.SQLState(), true
+		}
+		val := reflect.Indirect(reflect.ValueOf(err))
+		if val.Kind() == reflect.Struct {
+			if code := val.FieldByName("Code"); code.IsValid() && code.Kind() == reflect.String {
+				return code.String(), true
+			}
+		}
+	}
+	return "", false
+}
 
 func newDatasetAdapter(ds *goqu.Dataset) goqu.Adapter {
 	ret := goqu.NewDefaultAdapter(ds).(*goqu.DefaultAdapter)
 	ret.PlaceHolderRune = placeholder_rune
 	ret.IncludePlaceholderNum = true
-	return ret
+	ret.MaxPlaceholders = max_placeholders
+	return &DatasetAdapter{ret}
 }
 
 func init() {
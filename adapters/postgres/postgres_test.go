@@ -333,6 +333,49 @@ func (me *postgresTest) TestDelete() {
 	assert.Equal(t, id, e.Id)
 }
 
+func (me *postgresTest) TestWrap_Nested() {
+	t := me.T()
+	tx, err := me.db.Begin()
+	assert.NoError(t, err)
+	err = tx.Wrap(func() error {
+		if _, err := tx.From("entry").Where(goqu.I("int").Eq(9)).Delete().Exec(); err != nil {
+			return err
+		}
+		innerErr := tx.Wrap(func() error {
+			if _, err := tx.From("entry").Where(goqu.I("int").Eq(8)).Delete().Exec(); err != nil {
+				return err
+			}
+			return fmt.Errorf("inner error")
+		})
+		assert.EqualError(t, innerErr, "inner error")
+		return tx.Wrap(func() error {
+			_, err := tx.From("entry").Where(goqu.I("int").Eq(7)).Delete().Exec()
+			return err
+		})
+	})
+	assert.NoError(t, err)
+
+	var ints []int
+	assert.NoError(t, me.db.From("entry").Select("int").Where(goqu.I("int").Gte(7)).Order(goqu.I("int").Asc()).ScanVals(&ints))
+	assert.Equal(t, ints, []int{8})
+}
+
+func (me *postgresTest) TestSavepoint() {
+	t := me.T()
+	tx, err := me.db.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Savepoint("before_delete"))
+	_, err = tx.From("entry").Delete().Exec()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.RollbackTo("before_delete"))
+	assert.NoError(t, tx.Release("before_delete"))
+	assert.NoError(t, tx.Commit())
+
+	count, err := me.db.From("entry").Count()
+	assert.NoError(t, err)
+	assert.Equal(t, count, 10)
+}
+
 func TestPostgresSuite(t *testing.T) {
 	suite.Run(t, new(postgresTest))
 }
@@ -1,10 +1,12 @@
 package sqlite3
 
 import (
+	"fmt"
 	"regexp"
 	"testing"
 
 	"github.com/doug-martin/goqu"
+	driver "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -63,6 +65,52 @@ func (me *datasetAdapterTest) TestSupportsOrderByOnUpdate() {
 	assert.True(t, dsAdapter.SupportsLimitOnDelete())
 }
 
+func (me *datasetAdapterTest) TestSeekAfter() {
+	t := me.T()
+	cursor, err := goqu.EncodeCursor("Test1", int64(10))
+	assert.NoError(t, err)
+	seek, err := me.GetDs("test").Order(goqu.I("name").Asc(), goqu.I("id").Asc()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, _, err := seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "SELECT * FROM `test` WHERE (`name`, `id`) > ('Test1', 10) ORDER BY `name` ASC, `id` ASC")
+
+	seek, err = me.GetDs("test").Order(goqu.I("name").Desc(), goqu.I("id").Desc()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, _, err = seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "SELECT * FROM `test` WHERE (((`name` < 'Test1') OR (`name` IS NULL)) OR ((`name` = 'Test1') AND ((`id` < 10) OR (`id` IS NULL)))) ORDER BY `name` DESC, `id` DESC")
+}
+
+func (me *datasetAdapterTest) TestInsertVariants() {
+	t := me.T()
+	ds := me.GetDs("test")
+	item := goqu.Record{"id": 1}
+	sql, _, err := ds.InsertIgnore().ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "INSERT OR IGNORE INTO `test` (`id`) VALUES (1)")
+
+	sql, _, err = ds.Replace().ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "INSERT OR REPLACE INTO `test` (`id`) VALUES (1)")
+
+	sql, _, err = ds.InsertAs(goqu.INSERT_ABORT).ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "INSERT OR ABORT INTO `test` (`id`) VALUES (1)")
+
+	sql, _, err = ds.InsertAs(goqu.INSERT_FAIL).ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "INSERT OR FAIL INTO `test` (`id`) VALUES (1)")
+
+	sql, _, err = ds.InsertAs(goqu.INSERT_ROLLBACK).ToInsertSql(me.GetDs("other"))
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "INSERT OR ROLLBACK INTO `test` SELECT * FROM `other`")
+
+	sql, _, err = ds.Replace().InsertAs(goqu.INSERT_DEFAULT).ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, "INSERT INTO `test` (`id`) VALUES (1)")
+}
+
 func (me *datasetAdapterTest) TestIdentifiers() {
 	t := me.T()
 	ds := me.GetDs("test")
@@ -178,6 +226,43 @@ func (me *datasetAdapterTest) TestBooleanOperations() {
 
 }
 
+func (me *datasetAdapterTest) TestSavepointSql() {
+	t := me.T()
+	dsAdapter := me.GetDs("test").Adapter()
+	buf := goqu.NewSqlBuilder(false)
+	assert.NoError(t, dsAdapter.SavepointSql(buf, "sp1"))
+	assert.Equal(t, buf.String(), "SAVEPOINT `sp1`")
+	buf = goqu.NewSqlBuilder(false)
+	assert.NoError(t, dsAdapter.RollbackToSavepointSql(buf, "sp1"))
+	assert.Equal(t, buf.String(), "ROLLBACK TO SAVEPOINT `sp1`")
+	buf = goqu.NewSqlBuilder(false)
+	assert.NoError(t, dsAdapter.ReleaseSavepointSql(buf, "sp1"))
+	assert.Equal(t, buf.String(), "RELEASE SAVEPOINT `sp1`")
+}
+
+func (me *datasetAdapterTest) TestIsRetryableTxError() {
+	t := me.T()
+	dsAdapter := me.GetDs("test").Adapter()
+	assert.True(t, dsAdapter.IsRetryableTxError(driver.Error{Code: driver.ErrBusy}))
+	assert.True(t, dsAdapter.IsRetryableTxError(driver.Error{Code: driver.ErrLocked}))
+	assert.True(t, dsAdapter.IsRetryableTxError(fmt.Errorf("wrapped: %w", driver.Error{Code: driver.ErrBusy})))
+	assert.False(t, dsAdapter.IsRetryableTxError(driver.Error{Code: driver.ErrConstraint}))
+	assert.False(t, dsAdapter.IsRetryableTxError(fmt.Errorf("database is locked")))
+	assert.False(t, dsAdapter.IsRetryableTxError(nil))
+}
+
+func (me *datasetAdapterTest) TestClassifyError() {
+	t := me.T()
+	dsAdapter := me.GetDs("test").Adapter()
+	assert.Equal(t, dsAdapter.ClassifyError(driver.Error{Code: driver.ErrConstraint, ExtendedCode: driver.ErrConstraintUnique}), goqu.ErrUniqueViolation)
+	assert.Equal(t, dsAdapter.ClassifyError(driver.Error{Code: driver.ErrConstraint, ExtendedCode: driver.ErrConstraintPrimaryKey}), goqu.ErrUniqueViolation)
+	assert.Equal(t, dsAdapter.ClassifyError(driver.Error{Code: driver.ErrConstraint, ExtendedCode: driver.ErrConstraintForeignKey}), goqu.ErrForeignKeyViolation)
+	assert.Equal(t, dsAdapter.ClassifyError(driver.Error{Code: driver.ErrConstraint, ExtendedCode: driver.ErrConstraintNotNull}), goqu.ErrNotNullViolation)
+	assert.Equal(t, dsAdapter.ClassifyError(fmt.Errorf("wrapped: %w", driver.Error{Code: driver.ErrConstraint, ExtendedCode: driver.ErrConstraintUnique})), goqu.ErrUniqueViolation)
+	assert.Nil(t, dsAdapter.ClassifyError(driver.Error{Code: driver.ErrBusy}))
+	assert.Nil(t, dsAdapter.ClassifyError(fmt.Errorf("UNIQUE constraint failed")))
+}
+
 func TestDatasetAdapterSuite(t *testing.T) {
 	suite.Run(t, new(datasetAdapterTest))
 }
@@ -1,6 +1,10 @@
 package sqlite3
 
 import (
+	"errors"
+	"reflect"
+	"strings"
+
 	"github.com/doug-martin/goqu"
 )
 
@@ -12,6 +16,7 @@ var (
 	sqlite3_true        = []byte("1")
 	sqlite3_false       = []byte("0")
 	time_format         = "2006-01-02 15:04:05"
+	max_placeholders    = 999 //the default SQLITE_MAX_VARIABLE_NUMBER of sqlite versions before 3.32.0
 	operator_lookup     = map[goqu.BooleanOperation][]byte{
 		goqu.EQ_OP:                []byte("="),
 		goqu.NEQ_OP:               []byte("!="),
@@ -32,8 +37,29 @@ var (
 		goqu.REGEXP_I_LIKE_OP:     []byte("REGEXP"),
 		goqu.REGEXP_NOT_I_LIKE_OP: []byte("NOT REGEXP"),
 	}
+	insert_variant_lookup = map[goqu.InsertVariant][]byte{
+		goqu.INSERT_IGNORE:   []byte("INSERT OR IGNORE INTO"),
+		goqu.INSERT_REPLACE:  []byte("INSERT OR REPLACE INTO"),
+		goqu.INSERT_ABORT:    []byte("INSERT OR ABORT INTO"),
+		goqu.INSERT_FAIL:     []byte("INSERT OR FAIL INTO"),
+		goqu.INSERT_ROLLBACK: []byte("INSERT OR ROLLBACK INTO"),
+	}
 )
 
+//Result codes of errors that can be retried (SQLITE_BUSY, SQLITE_LOCKED)
+var retryable_error_codes = map[int64]bool{
+	5: true,
+	6: true,
+}
+
+//Extended result codes of the errors classified by ClassifyError
+var extended_error_code_kinds = map[int64]error{
+	2067: goqu.ErrUniqueViolation,     //SQLITE_CONSTRAINT_UNIQUE
+	1555: goqu.ErrUniqueViolation,     //SQLITE_CONSTRAINT_PRIMARYKEY
+	787:  goqu.ErrForeignKeyViolation, //SQLITE_CONSTRAINT_FOREIGNKEY
+	1299: goqu.ErrNotNullViolation,    //SQLITE_CONSTRAINT_NOTNULL
+}
+
 type DatasetAdapter struct {
 	*goqu.DefaultAdapter
 }
@@ -58,6 +84,15 @@ func (me *DatasetAdapter) SupportsOrderByOnUpdate() bool {
 	return true
 }
 
+//sqlite3 sorts NULLs as if they are smaller than any other value
+func (me *DatasetAdapter) SortsNullsFirst() bool {
+	return true
+}
+
+func (me *DatasetAdapter) SupportsArrays() bool {
+	return false
+}
+
 func (me *DatasetAdapter) LiteralString(buf *goqu.SqlBuilder, s string) error {
 	if buf.IsPrepared {
 		return me.PlaceHolderSql(buf, s)
@@ -86,6 +121,57 @@ func (me *DatasetAdapter) LiteralString(buf *goqu.SqlBuilder, s string) error {
 	return nil
 }
 
+//Generates SQL for a JSON encoded value. The quotes of the JSON are not escaped with backslashes like LiteralString, sqlite
+//would store the backslashes and the JSON would no longer be valid, so only single quotes are doubled.
+func (me *DatasetAdapter) LiteralJson(buf *goqu.SqlBuilder, json []byte) error {
+	if buf.IsPrepared {
+		return me.PlaceHolderSql(buf, string(json))
+	}
+	buf.WriteRune(singlq_quote)
+	buf.WriteString(strings.Replace(string(json), "'", "''", -1))
+	buf.WriteRune(singlq_quote)
+	return nil
+}
+
+//Returns true if the database was busy or locked
+func (me *DatasetAdapter) IsRetryableTxError(err error) bool {
+	code, ok := errorCode(err)
+	return ok && retryable_error_codes[code]
+}
+
+//Classifies a driver error by its extended result code
+func (me *DatasetAdapter) ClassifyError(err error) error {
+	if code, ok := errorField(err, "ExtendedCode"); ok {
+		if kind, ok := extended_error_code_kinds[code]; ok {
+			return kind
+		}
+	}
+	return me.DefaultAdapter.ClassifyError(err)
+}
+
+//Returns the primary result code of a driver error with an integer Code field (e.g. sqlite3.Error)
+func errorCode(err error) (int64, bool) {
+	return errorField(err, "Code")
+}
+
+//Returns the value of an integer field of a driver error (e.g. the Code or ExtendedCode of a sqlite3.Error). The adapter
+//does not import the driver so the field is read by name, this is best effort and an error whose code cannot be read is
+//not classified. See TestClassifyError for the driver types it is tested against.
+func errorField(err error, name string) (int64, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		val := reflect.Indirect(reflect.ValueOf(err))
+		if val.Kind() == reflect.Struct {
+			if code := val.FieldByName(name); code.IsValid() {
+				switch code.Kind() {
+				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+					return code.Int(), true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
 func newDatasetAdapter(ds *goqu.Dataset) goqu.Adapter {
 	def := goqu.NewDefaultAdapter(ds).(*goqu.DefaultAdapter)
 	def.PlaceHolderRune = placeholder_rune
@@ -96,7 +182,9 @@ func newDatasetAdapter(ds *goqu.Dataset) goqu.Adapter {
 	def.False = sqlite3_false
 	def.TimeFormat = time_format
 	def.BooleanOperatorLookup = operator_lookup
+	def.InsertVariantLookup = insert_variant_lookup
 	def.UseLiteralIsBools = false
+	def.MaxPlaceholders = max_placeholders
 	return &DatasetAdapter{def}
 }
 
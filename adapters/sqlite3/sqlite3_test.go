@@ -1,13 +1,16 @@
 package sqlite3
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/doug-martin/goqu"
-	_ "github.com/mattn/go-sqlite3"
+	driver "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -207,6 +210,315 @@ func (me *sqlite3Test) TestCount() {
 	assert.Equal(t, count, 0)
 }
 
+func (me *sqlite3Test) TestScanMaps() {
+	t := me.T()
+	var records []goqu.Record
+	assert.NoError(t, me.db.From("entry").Select("int", "string", "bytes").Where(goqu.I("int").Lt(2)).Order(goqu.I("id").Asc()).ScanMaps(&records))
+	assert.Equal(t, records, []goqu.Record{
+		{"int": int64(0), "string": "0.000000", "bytes": "0.000000"},
+		{"int": int64(1), "string": "0.100000", "bytes": "0.100000"},
+	})
+
+	var record goqu.Record
+	found, err := me.db.From("entry").Select("id", "string").Where(goqu.I("int").Eq(9)).ScanMap(&record)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, record, goqu.Record{"id": int64(10), "string": "0.900000"})
+}
+
+func (me *sqlite3Test) TestScanStructs_Nested() {
+	t := me.T()
+	type nestedEntry struct {
+		Entry entry  `db:"entry"`
+		Next  *entry `db:"next"`
+	}
+	var entries []nestedEntry
+	assert.NoError(t, me.db.From("entry").
+		LeftJoin(goqu.I("entry").As("next"), goqu.On(goqu.I("next.int").Eq(goqu.L(`"entry"."int" + 1`)))).
+		SelectNested(nestedEntry{}).
+		Where(goqu.I("entry.int").Gte(8)).
+		Order(goqu.I("entry.id").Asc()).
+		ScanStructs(&entries))
+	assert.Len(t, entries, 2)
+	assert.Equal(t, entries[0].Entry.Int, 8)
+	assert.Equal(t, entries[0].Entry.String, "0.800000")
+	assert.NotNil(t, entries[0].Next)
+	assert.Equal(t, entries[0].Next.Int, 9)
+	assert.Equal(t, entries[0].Next.Time, entries[1].Entry.Time)
+	assert.Equal(t, entries[1].Entry.Int, 9)
+	assert.Nil(t, entries[1].Next)
+}
+
+type upperString string
+
+func (me *upperString) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		*me = upperString(strings.ToUpper(v))
+	case []byte:
+		*me = upperString(strings.ToUpper(string(v)))
+	default:
+		return fmt.Errorf("cannot scan %T into upperString", src)
+	}
+	return nil
+}
+
+func (me *sqlite3Test) TestScan_Nullable() {
+	t := me.T()
+	type nullableEntry struct {
+		Id     int64          `db:"id" goqu:"skipinsert"`
+		String *string        `db:"string"`
+		Time   *time.Time     `db:"time"`
+		Int    sql.NullInt64  `db:"int"`
+		Float  sql.NullString `db:"float"`
+		Upper  upperString    `db:"upper"`
+	}
+	_, err := me.db.Exec("DROP TABLE IF EXISTS `nullable_entry`")
+	assert.NoError(t, err)
+	_, err = me.db.Exec("CREATE TABLE `nullable_entry` (`id` INTEGER PRIMARY KEY, `string` VARCHAR(255), `time` DATETIME, `int` INT, `float` FLOAT, `upper` VARCHAR(255) NOT NULL)")
+	assert.NoError(t, err)
+
+	str, now := "Test1", time.Now().UTC().Truncate(time.Second)
+	ds := me.db.From("nullable_entry")
+	_, err = ds.Insert(
+		nullableEntry{String: &str, Time: &now, Int: sql.NullInt64{Int64: 10, Valid: true}, Float: sql.NullString{String: "1.5", Valid: true}, Upper: "a"},
+		nullableEntry{Upper: "b"},
+	).Exec()
+	assert.NoError(t, err)
+
+	var entries []nullableEntry
+	assert.NoError(t, ds.Order(goqu.I("id").Asc()).ScanStructs(&entries))
+	assert.Len(t, entries, 2)
+	assert.Equal(t, *entries[0].String, "Test1")
+	assert.True(t, entries[0].Time.Equal(now))
+	assert.Equal(t, entries[0].Int, sql.NullInt64{Int64: 10, Valid: true})
+	assert.Equal(t, entries[0].Float, sql.NullString{String: "1.5", Valid: true})
+	assert.Equal(t, entries[0].Upper, upperString("A"))
+	assert.Nil(t, entries[1].String)
+	assert.Nil(t, entries[1].Time)
+	assert.False(t, entries[1].Int.Valid)
+	assert.False(t, entries[1].Float.Valid)
+	assert.Equal(t, entries[1].Upper, upperString("B"))
+
+	var strs []*string
+	assert.NoError(t, ds.Select("string").Order(goqu.I("id").Asc()).ScanVals(&strs))
+	assert.Len(t, strs, 2)
+	assert.Equal(t, *strs[0], "Test1")
+	assert.Nil(t, strs[1])
+
+	var ints []sql.NullInt64
+	assert.NoError(t, ds.Select("int").Order(goqu.I("id").Asc()).ScanVals(&ints))
+	assert.Equal(t, ints, []sql.NullInt64{{Int64: 10, Valid: true}, {}})
+
+	var uppers []upperString
+	assert.NoError(t, ds.Select("upper").Order(goqu.I("id").Asc()).ScanVals(&uppers))
+	assert.Equal(t, uppers, []upperString{"A", "B"})
+
+	var tm *time.Time
+	found, err := ds.Select("time").Where(goqu.I("id").Eq(entries[1].Id)).ScanVal(&tm)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Nil(t, tm)
+}
+
+func (me *sqlite3Test) TestJson() {
+	t := me.T()
+	type settings struct {
+		Theme string `json:"theme"`
+	}
+	type jsonEntry struct {
+		Id       int64             `db:"id" goqu:"skipinsert"`
+		Tags     []string          `db:"tags" goqu:"json"`
+		Attrs    map[string]string `db:"attrs" goqu:"json"`
+		Settings *settings         `db:"settings" goqu:"json"`
+	}
+	_, err := me.db.Exec("DROP TABLE IF EXISTS `json_entry`")
+	assert.NoError(t, err)
+	_, err = me.db.Exec("CREATE TABLE `json_entry` (`id` INTEGER PRIMARY KEY, `tags` TEXT, `attrs` TEXT, `settings` TEXT)")
+	assert.NoError(t, err)
+
+	ds := me.db.From("json_entry")
+	_, err = ds.Insert(
+		jsonEntry{Tags: []string{"a", "b's"}, Attrs: map[string]string{"k": `v"1`}, Settings: &settings{Theme: "dark"}},
+		jsonEntry{},
+	).Exec()
+	assert.NoError(t, err)
+
+	var entries []jsonEntry
+	assert.NoError(t, ds.Order(goqu.I("id").Asc()).ScanStructs(&entries))
+	assert.Equal(t, entries, []jsonEntry{
+		{Id: 1, Tags: []string{"a", "b's"}, Attrs: map[string]string{"k": `v"1`}, Settings: &settings{Theme: "dark"}},
+		{Id: 2},
+	})
+
+	entries[1].Tags = []string{"c"}
+	_, err = ds.Prepared(true).Where(goqu.I("id").Eq(2)).Update(entries[1]).Exec()
+	assert.NoError(t, err)
+	var entry jsonEntry
+	found, err := ds.Where(goqu.I("id").Eq(2)).ScanStruct(&entry)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, entry, jsonEntry{Id: 2, Tags: []string{"c"}})
+}
+
+func (me *sqlite3Test) TestOnConflict() {
+	t := me.T()
+	type upsertEntry struct {
+		Name  string `db:"name"`
+		Count int64  `db:"count"`
+	}
+	_, err := me.db.Exec("DROP TABLE IF EXISTS `upsert_entry`")
+	assert.NoError(t, err)
+	_, err = me.db.Exec("CREATE TABLE `upsert_entry` (`name` VARCHAR(255) PRIMARY KEY, `count` INT NOT NULL)")
+	assert.NoError(t, err)
+
+	ds := me.db.From("upsert_entry")
+	_, err = ds.Insert(upsertEntry{Name: "a", Count: 1}, upsertEntry{Name: "b", Count: 1}).Exec()
+	assert.NoError(t, err)
+
+	_, err = ds.OnConflict("name", goqu.DoNothing()).Insert(upsertEntry{Name: "a", Count: 10}).Exec()
+	assert.NoError(t, err)
+	_, err = ds.Prepared(true).OnConflict("name", goqu.DoUpdate(
+		goqu.Record{"count": goqu.L("? + ?", goqu.I("upsert_entry.count"), goqu.Excluded("count"))},
+		goqu.I("upsert_entry.name").Neq("b"),
+	)).Insert(upsertEntry{Name: "a", Count: 2}, upsertEntry{Name: "b", Count: 2}, upsertEntry{Name: "c", Count: 2}).Exec()
+	assert.NoError(t, err)
+
+	var entries []upsertEntry
+	assert.NoError(t, ds.Order(goqu.I("name").Asc()).ScanStructs(&entries))
+	assert.Equal(t, entries, []upsertEntry{{Name: "a", Count: 3}, {Name: "b", Count: 1}, {Name: "c", Count: 2}})
+}
+
+func (me *sqlite3Test) TestInsertVariants() {
+	t := me.T()
+	type variantEntry struct {
+		Name  string `db:"name"`
+		Count int64  `db:"count"`
+	}
+	_, err := me.db.Exec("DROP TABLE IF EXISTS `variant_entry`")
+	assert.NoError(t, err)
+	_, err = me.db.Exec("CREATE TABLE `variant_entry` (`name` VARCHAR(255) PRIMARY KEY, `count` INT NOT NULL)")
+	assert.NoError(t, err)
+
+	ds := me.db.From("variant_entry")
+	_, err = ds.Insert(variantEntry{Name: "a", Count: 1}, variantEntry{Name: "b", Count: 1}).Exec()
+	assert.NoError(t, err)
+
+	result, err := ds.InsertIgnore().Insert(variantEntry{Name: "a", Count: 2}, variantEntry{Name: "c", Count: 2}).Exec()
+	assert.NoError(t, err)
+	inserted, err := result.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, inserted, int64(1))
+
+	_, err = ds.Prepared(true).Replace().Insert(variantEntry{Name: "b", Count: 3}).Exec()
+	assert.NoError(t, err)
+
+	_, err = ds.InsertAs(goqu.INSERT_FAIL).Insert(variantEntry{Name: "d", Count: 4}, variantEntry{Name: "a", Count: 4}).Exec()
+	assert.Error(t, err)
+
+	var entries []variantEntry
+	assert.NoError(t, ds.Order(goqu.I("name").Asc()).ScanStructs(&entries))
+	assert.Equal(t, entries, []variantEntry{{Name: "a", Count: 1}, {Name: "b", Count: 3}, {Name: "c", Count: 2}, {Name: "d", Count: 4}})
+}
+
+func (me *sqlite3Test) TestNameMapper() {
+	t := me.T()
+	type mappedEntry struct {
+		Id          uint32 `goqu:"skipinsert,skipupdate"`
+		Int         int
+		FloatValue  float64 `db:"float"`
+		StringValue string  `db:"string"`
+		Time        time.Time
+		Bool        bool
+		Bytes       []byte
+	}
+	sqlDb, err := sql.Open("sqlite3", db_uri)
+	assert.NoError(t, err)
+	sqlDb.SetMaxOpenConns(1)
+	db := goqu.New("sqlite3", sqlDb)
+	db.NameMapper(goqu.SnakeCaseMapper())
+	_, err = db.Exec(create_table)
+	assert.NoError(t, err)
+
+	ds := db.From("entry")
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err = ds.Insert(mappedEntry{Int: 10, FloatValue: 1.5, StringValue: "Test1", Time: now, Bool: true, Bytes: []byte("Test1")}).Exec()
+	assert.NoError(t, err)
+
+	var e mappedEntry
+	found, err := ds.Where(goqu.I("int").Eq(10)).ScanStruct(&e)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, e.StringValue, "Test1")
+	assert.Equal(t, e.FloatValue, 1.5)
+	assert.True(t, e.Time.Equal(now))
+
+	e.StringValue = "Test2"
+	_, err = ds.Where(goqu.I("id").Eq(e.Id)).Update(e).Exec()
+	assert.NoError(t, err)
+	var str string
+	_, err = ds.Select("string").Where(goqu.I("id").Eq(e.Id)).ScanVal(&str)
+	assert.NoError(t, err)
+	assert.Equal(t, str, "Test2")
+}
+
+func (me *sqlite3Test) TestStatementCacheInTx() {
+	t := me.T()
+	sqlDb, err := sql.Open("sqlite3", db_uri)
+	assert.NoError(t, err)
+	sqlDb.SetMaxOpenConns(1)
+	db := goqu.New("sqlite3", sqlDb)
+	db.StatementCache(10)
+	_, err = db.Exec("CREATE TABLE `stmt_entry` (`id` INTEGER PRIMARY KEY, `int` INT)")
+	assert.NoError(t, err)
+
+	//the transaction holds the only connection so the statements must be prepared on the transaction
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var count int64
+	err = db.WithTxContext(ctx, nil, func(tx *goqu.TxDatabase) error {
+		ds := tx.From("stmt_entry").Prepared(true).WithContext(ctx)
+		if _, err := ds.Insert(goqu.Record{"int": 10}).Exec(); err != nil {
+			return err
+		}
+		_, err := ds.Select(goqu.COUNT(goqu.Star())).Where(goqu.I("int").Eq(10)).ScanVal(&count)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, count, int64(1))
+}
+
+func (me *sqlite3Test) TestPaginateNulls() {
+	t := me.T()
+	type pageEntry struct {
+		Id      int64      `db:"id"`
+		Created *time.Time `db:"created"`
+	}
+	_, err := me.db.Exec("DROP TABLE IF EXISTS `page_entry`")
+	assert.NoError(t, err)
+	_, err = me.db.Exec("CREATE TABLE `page_entry` (`id` INTEGER PRIMARY KEY, `created` DATETIME)")
+	assert.NoError(t, err)
+	created := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = me.db.From("page_entry").Insert(
+		pageEntry{Id: 1}, pageEntry{Id: 2}, pageEntry{Id: 3, Created: &created}, pageEntry{Id: 4, Created: &created},
+	).Exec()
+	assert.NoError(t, err)
+
+	//sqlite sorts NULLs first in ascending order
+	for _, ds := range []*goqu.Dataset{
+		me.db.From("page_entry").Order(goqu.I("created").Asc(), goqu.I("id").Asc()),
+		me.db.From("page_entry").Order(goqu.I("created").Desc(), goqu.I("id").Desc()),
+	} {
+		var entries []pageEntry
+		next, err := ds.Paginate(&entries, 2, "")
+		for err == nil && next != "" {
+			next, err = ds.Paginate(&entries, 2, next)
+		}
+		assert.NoError(t, err)
+		assert.Len(t, entries, 4)
+	}
+}
+
 func (me *sqlite3Test) TestInsert() {
 	t := me.T()
 	ds := me.db.From("entry")
@@ -247,6 +559,38 @@ func (me *sqlite3Test) TestInsert() {
 	assert.Len(t, newEntries, 4)
 }
 
+func (me *sqlite3Test) TestInsert_Chunked() {
+	t := me.T()
+	ds := me.db.From("entry")
+	now := time.Now()
+	var entries []entry
+	for i := 0; i < 500; i++ {
+		entries = append(entries, entry{Int: 100 + i, Float: float64(i), String: fmt.Sprint(i), Time: now, Bool: i%2 == 0, Bytes: []byte(fmt.Sprint(i))})
+	}
+	result, err := ds.Prepared(true).ChunkInserts(goqu.InsertChunkOptions{InTx: true}).Insert(entries).Exec()
+	assert.NoError(t, err)
+	inserted, err := result.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, inserted, int64(500))
+
+	count, err := ds.Where(goqu.I("int").Gte(100)).Count()
+	assert.NoError(t, err)
+	assert.Equal(t, count, int64(500))
+}
+
+func (me *sqlite3Test) TestInsert_UniqueViolation() {
+	t := me.T()
+	ds := me.db.From("entry")
+	_, err := ds.Insert(goqu.Record{"id": 1, "int": 10, "float": 1.0, "string": "1.000000", "time": time.Now(), "bool": true, "bytes": []byte("1.000000")}).Exec()
+	assert.True(t, errors.Is(err, goqu.ErrUniqueViolation))
+	var dbErr *goqu.DatabaseError
+	assert.True(t, errors.As(err, &dbErr))
+	assert.Equal(t, dbErr.Op, "EXEC")
+	var sqliteErr driver.Error
+	assert.True(t, errors.As(err, &sqliteErr))
+	assert.Equal(t, sqliteErr.ExtendedCode, driver.ErrConstraintPrimaryKey)
+}
+
 func (me *sqlite3Test) TestInsertReturning() {
 	t := me.T()
 	ds := me.db.From("entry")
@@ -312,6 +656,49 @@ func (me *sqlite3Test) TestDelete() {
 	assert.Equal(t, err.Error(), "goqu: Adapter does not support RETURNING clause")
 }
 
+func (me *sqlite3Test) TestWrap_Nested() {
+	t := me.T()
+	tx, err := me.db.Begin()
+	assert.NoError(t, err)
+	err = tx.Wrap(func() error {
+		if _, err := tx.From("entry").Where(goqu.I("int").Eq(9)).Delete().Exec(); err != nil {
+			return err
+		}
+		innerErr := tx.Wrap(func() error {
+			if _, err := tx.From("entry").Where(goqu.I("int").Eq(8)).Delete().Exec(); err != nil {
+				return err
+			}
+			return fmt.Errorf("inner error")
+		})
+		assert.EqualError(t, innerErr, "inner error")
+		return tx.Wrap(func() error {
+			_, err := tx.From("entry").Where(goqu.I("int").Eq(7)).Delete().Exec()
+			return err
+		})
+	})
+	assert.NoError(t, err)
+
+	var ints []int
+	assert.NoError(t, me.db.From("entry").Select("int").Where(goqu.I("int").Gte(7)).Order(goqu.I("int").Asc()).ScanVals(&ints))
+	assert.Equal(t, ints, []int{8})
+}
+
+func (me *sqlite3Test) TestSavepoint() {
+	t := me.T()
+	tx, err := me.db.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Savepoint("before_delete"))
+	_, err = tx.From("entry").Delete().Exec()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.RollbackTo("before_delete"))
+	assert.NoError(t, tx.Release("before_delete"))
+	assert.NoError(t, tx.Commit())
+
+	count, err := me.db.From("entry").Count()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), count)
+}
+
 func TestSqlite3Suite(t *testing.T) {
 	suite.Run(t, new(sqlite3Test))
 }
@@ -0,0 +1,421 @@
+package goqu
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//the formats of the time.Time elements of an array, elements are encoded using time.RFC3339Nano and postgres returns
+//timestamp, timestamptz and date elements in the other formats
+var array_time_formats = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+type (
+	//An Expression that represents an array value (e.g. a postgres text[] or int[])
+	//    Array([]string{"a", "b"}) -> ARRAY['a', 'b']
+	ArrayExpression interface {
+		Expression
+		AliasMethods
+		ArrayMethods
+		//Returns the elements of the array, nil if the array was not created with a slice
+		Values() []interface{}
+		//Returns the array in the postgres array format (e.g. {"a","b"}), so it can be bound to a prepared statement
+		Value() (driver.Value, error)
+		//Scans an array column in the postgres array format into the slice, the array must be created with a pointer to a
+		//slice
+		Scan(src interface{}) error
+	}
+	array struct {
+		values interface{}
+	}
+	//used internally to scan an array column into a slice field of a struct
+	arrayScanner struct {
+		dest reflect.Value
+	}
+	//used internally to parse an array in the postgres array format
+	arrayParser struct {
+		s   string
+		pos int
+	}
+)
+
+//Creates a new ArrayExpression from a slice, the postgres adapter writes the array as ARRAY[...] or binds it as a single
+//argument when the dataset is prepared. Use a pointer to a slice to scan an array column with ScanVal. If values is not a
+//slice or a pointer to a slice an error is returned when the SQL is generated.
+//    I("tags").Contains(Array([]string{"a", "b"})) -> ("tags" @> ARRAY['a', 'b'])
+//
+//    var tags []string
+//    found, err := db.From("items").Select("tags").Where(I("id").Eq(1)).ScanVal(Array(&tags))
+func Array(values interface{}) ArrayExpression {
+	return &array{values: values}
+}
+
+//used internally to check that the array was created with a slice or a pointer to a slice
+func (me *array) validate() error {
+	if reflect.Indirect(reflect.ValueOf(me.values)).Kind() != reflect.Slice {
+		return NewGoquError("Array must be created with a slice or a pointer to a slice got %T", me.values)
+	}
+	return nil
+}
+
+func (me *array) Clone() Expression {
+	return &array{values: me.values}
+}
+
+func (me *array) Expression() Expression               { return me }
+func (me *array) As(val interface{}) AliasedExpression { return aliased(me, val) }
+
+//Returns a BooleanExpression for checking that an array contains all of the elements of another array (e.g "tags" @> ARRAY['a'])
+func (me *array) Contains(val interface{}) BooleanExpression {
+	return arrayOp(CONTAINS_OP, me, val)
+}
+
+//Returns a BooleanExpression for checking that all of the elements of an array are in another array (e.g "tags" <@ ARRAY['a'])
+func (me *array) ContainedBy(val interface{}) BooleanExpression {
+	return arrayOp(CONTAINED_BY_OP, me, val)
+}
+
+//Returns a BooleanExpression for checking that an array has elements in common with another array (e.g "tags" && ARRAY['a'])
+func (me *array) Overlaps(val interface{}) BooleanExpression {
+	return arrayOp(OVERLAPS_OP, me, val)
+}
+
+//Returns a BooleanExpression for checking that a value is equal to any of the elements of an array (e.g "id" = ANY(ARRAY[1]))
+func (me *array) EqAny(val interface{}) BooleanExpression {
+	return arrayOp(EQ_ANY_OP, me, val)
+}
+
+func (me *array) Values() []interface{} {
+	if me.validate() != nil {
+		return nil
+	}
+	slice := reflect.Indirect(reflect.ValueOf(me.values))
+	values := make([]interface{}, slice.Len())
+	for i := range values {
+		values[i] = slice.Index(i).Interface()
+	}
+	return values
+}
+
+func (me *array) Value() (driver.Value, error) {
+	if err := me.validate(); err != nil {
+		return nil, err
+	}
+	var buf strings.Builder
+	if err := encodeArray(&buf, reflect.Indirect(reflect.ValueOf(me.values))); err != nil {
+		return nil, err
+	}
+	return buf.String(), nil
+}
+
+func (me *array) Scan(src interface{}) error {
+	val := reflect.ValueOf(me.values)
+	if val.Kind() != reflect.Ptr {
+		return NewGoquError("Array must be created with a pointer to a slice to be scanned")
+	}
+	return arrayScanner{dest: val.Elem()}.Scan(src)
+}
+
+//used internally to create a BooleanExpression for an array operator, a slice is converted to an ArrayExpression
+func arrayOp(op BooleanOperation, lhs Expression, rhs interface{}) BooleanExpression {
+	if _, ok := rhs.(Expression); !ok && rhs != nil && isArrayType(reflect.TypeOf(rhs)) {
+		rhs = Array(rhs)
+	}
+	return boolean{op: op, lhs: lhs, rhs: rhs}
+}
+
+//used internally to check if a type is a slice that is scanned from and inserted as an array, []byte and types that
+//implement sql.Scanner or driver.Valuer (e.g. pq.StringArray) are not
+func isArrayType(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice || t.Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+	return !reflect.PtrTo(t).Implements(scanner_type) && !t.Implements(valuer_type)
+}
+
+//used internally to write a slice in the postgres array format
+func encodeArray(buf *strings.Builder, slice reflect.Value) error {
+	buf.WriteByte('{')
+	for i := 0; i < slice.Len(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encodeArrayElem(buf, slice.Index(i)); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+//used internally to write an element of an array in the postgres array format
+func encodeArrayElem(buf *strings.Builder, val reflect.Value) error {
+	if (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) && val.IsNil() {
+		buf.WriteString("NULL")
+		return nil
+	}
+	if valuer, ok := val.Interface().(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return NewGoquError(err.Error())
+		}
+		return encodeArrayElem(buf, reflect.ValueOf(&v).Elem())
+	}
+	val = reflect.Indirect(val)
+	if val.Kind() == reflect.Interface {
+		val = val.Elem()
+	}
+	switch v := val.Interface().(type) {
+	case []byte:
+		quoteArrayElem(buf, string(v))
+		return nil
+	case time.Time:
+		quoteArrayElem(buf, v.Format(time.RFC3339Nano))
+		return nil
+	}
+	switch val.Kind() {
+	case reflect.String:
+		quoteArrayElem(buf, val.String())
+	case reflect.Bool:
+		buf.WriteString(strconv.FormatBool(val.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteString(strconv.FormatInt(val.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		buf.WriteString(strconv.FormatUint(val.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		buf.WriteString(strconv.FormatFloat(val.Float(), 'f', -1, val.Type().Bits()))
+	case reflect.Slice:
+		return encodeArray(buf, val)
+	default:
+		return NewGoquError("Unable to encode array element %T", val.Interface())
+	}
+	return nil
+}
+
+//used internally to write a quoted element of an array, quotes and backslashes are escaped with a backslash
+func quoteArrayElem(buf *strings.Builder, s string) {
+	buf.WriteByte('"')
+	for _, char := range s {
+		if char == '"' || char == '\\' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteRune(char)
+	}
+	buf.WriteByte('"')
+}
+
+//Decodes an array column in the postgres array format, the slice is set to nil if the column is NULL
+func (me arrayScanner) Scan(src interface{}) error {
+	me.dest.Set(reflect.Zero(me.dest.Type()))
+	var s string
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return NewGoquError("Unable to scan %T into an array", src)
+	}
+	elems, err := parseArray(s)
+	if err != nil {
+		return err
+	}
+	return setArray(me.dest, elems)
+}
+
+//used internally to parse an array in the postgres array format (e.g. {a,"b c",NULL}), a NULL element is nil and the
+//elements of a multi dimensional array are []interface{}
+func parseArray(s string) ([]interface{}, error) {
+	if strings.HasPrefix(s, "[") {
+		//skip the dimensions of an array that does not start at 1 (e.g. [0:1]={a,b})
+		s = s[strings.Index(s, "=")+1:]
+	}
+	p := &arrayParser{s: s}
+	elems, err := p.parse()
+	if err != nil || p.pos != len(s) {
+		return nil, NewGoquError("Unable to parse array %s", s)
+	}
+	return elems, nil
+}
+
+func (me *arrayParser) parse() ([]interface{}, error) {
+	if !me.next('{') {
+		return nil, NewGoquError("expected {")
+	}
+	elems := []interface{}{}
+	if me.next('}') {
+		return elems, nil
+	}
+	for {
+		me.skipSpaces()
+		if me.pos >= len(me.s) {
+			return nil, NewGoquError("unexpected end of array")
+		}
+		switch me.s[me.pos] {
+		case '{':
+			nested, err := me.parse()
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, nested)
+		case '"':
+			elems = append(elems, me.quoted())
+		default:
+			elems = append(elems, me.unquoted())
+		}
+		me.skipSpaces()
+		if me.next('}') {
+			return elems, nil
+		} else if !me.next(',') {
+			return nil, NewGoquError("expected , or }")
+		}
+	}
+}
+
+//used internally to consume the next character if it is c
+func (me *arrayParser) next(c byte) bool {
+	if me.pos < len(me.s) && me.s[me.pos] == c {
+		me.pos++
+		return true
+	}
+	return false
+}
+
+func (me *arrayParser) skipSpaces() {
+	for me.pos < len(me.s) && me.s[me.pos] == ' ' {
+		me.pos++
+	}
+}
+
+//used internally to read a quoted element, characters escaped with a backslash are read as is
+func (me *arrayParser) quoted() interface{} {
+	var buf strings.Builder
+	for me.pos++; me.pos < len(me.s) && me.s[me.pos] != '"'; me.pos++ {
+		if me.s[me.pos] == '\\' && me.pos+1 < len(me.s) {
+			me.pos++
+		}
+		buf.WriteByte(me.s[me.pos])
+	}
+	me.pos++
+	return buf.String()
+}
+
+//used internally to read an unquoted element, nil is returned for NULL
+func (me *arrayParser) unquoted() interface{} {
+	var buf strings.Builder
+	for ; me.pos < len(me.s) && me.s[me.pos] != ',' && me.s[me.pos] != '}'; me.pos++ {
+		if me.s[me.pos] == '\\' && me.pos+1 < len(me.s) {
+			me.pos++
+		}
+		buf.WriteByte(me.s[me.pos])
+	}
+	elem := strings.TrimRight(buf.String(), " ")
+	if strings.EqualFold(elem, "NULL") {
+		return nil
+	}
+	return elem
+}
+
+//used internally to set a slice, or a pointer to a slice, to the elements of a parsed array
+func setArray(dest reflect.Value, elems []interface{}) error {
+	if dest.Kind() == reflect.Ptr {
+		dest.Set(reflect.New(dest.Type().Elem()))
+		dest = dest.Elem()
+	}
+	if dest.Kind() == reflect.Interface {
+		dest.Set(reflect.ValueOf(elems))
+		return nil
+	}
+	if dest.Kind() != reflect.Slice {
+		return NewGoquError("Unable to scan array into %s", dest.Type())
+	}
+	slice := reflect.MakeSlice(dest.Type(), len(elems), len(elems))
+	for i, elem := range elems {
+		if err := setArrayElem(slice.Index(i), elem); err != nil {
+			return err
+		}
+	}
+	dest.Set(slice)
+	return nil
+}
+
+//used internally to set an element of a slice to an element of a parsed array
+func setArrayElem(dest reflect.Value, elem interface{}) error {
+	if e, ok := elem.([]interface{}); ok {
+		return setArray(dest, e)
+	}
+	if scanner, ok := dest.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(elem)
+	}
+	if elem == nil {
+		switch dest.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			return nil
+		}
+		return NewGoquError("Unable to scan NULL array element into %s", dest.Type())
+	}
+	s := elem.(string)
+	if dest.Kind() == reflect.Ptr {
+		dest.Set(reflect.New(dest.Type().Elem()))
+		return setArrayElem(dest.Elem(), elem)
+	}
+	if dest.Type() == time_type {
+		return setArrayTime(dest, s)
+	}
+	var err error
+	switch dest.Kind() {
+	case reflect.String:
+		dest.SetString(s)
+	case reflect.Interface:
+		dest.Set(reflect.ValueOf(s))
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		dest.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(s, 10, dest.Type().Bits())
+		dest.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, dest.Type().Bits())
+		dest.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(s, dest.Type().Bits())
+		dest.SetFloat(f)
+	case reflect.Slice:
+		if dest.Type().Elem().Kind() != reflect.Uint8 {
+			return NewGoquError("Unable to scan array element %s into %s", s, dest.Type())
+		}
+		dest.SetBytes([]byte(s))
+	default:
+		return NewGoquError("Unable to scan array element %s into %s", s, dest.Type())
+	}
+	if err != nil {
+		return NewGoquError("Unable to scan array element %s into %s", s, dest.Type())
+	}
+	return nil
+}
+
+//used internally to set a time.Time element of a slice, see array_time_formats
+func setArrayTime(dest reflect.Value, s string) error {
+	for _, format := range array_time_formats {
+		if t, err := time.Parse(format, s); err == nil {
+			dest.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	return NewGoquError("Unable to scan array element %s into %s", s, dest.Type())
+}
@@ -0,0 +1,143 @@
+package goqu
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type arrayTest struct {
+	suite.Suite
+}
+
+func (me *arrayTest) TestArray_NotSlice() {
+	t := me.T()
+	_, _, err := From("items").Where(I("tags").Contains(Array("a"))).ToSql()
+	assert.EqualError(t, err, "goqu: Array must be created with a slice or a pointer to a slice got string")
+	_, _, err = From("items").Prepared(true).Where(I("tags").Contains(Array(1))).ToSql()
+	assert.EqualError(t, err, "goqu: Array must be created with a slice or a pointer to a slice got int")
+	_, err = Array("a").Value()
+	assert.EqualError(t, err, "goqu: Array must be created with a slice or a pointer to a slice got string")
+	assert.Nil(t, Array("a").Values())
+}
+
+func (me *arrayTest) TestValue() {
+	t := me.T()
+	str := "c"
+	cases := []struct {
+		values   interface{}
+		expected string
+	}{
+		{[]string{}, `{}`},
+		{[]string{"a", `b"c`, `d\e`, "f,g"}, `{"a","b\"c","d\\e","f,g"}`},
+		{[]int64{1, -2}, `{1,-2}`},
+		{[]uint{1, 2}, `{1,2}`},
+		{[]float64{1.5, 2}, `{1.5,2}`},
+		{[]float32{1.1, 2}, `{1.1,2}`},
+		{[]bool{true, false}, `{true,false}`},
+		{[]*string{&str, nil}, `{"c",NULL}`},
+		{[]interface{}{1, "a", nil}, `{1,"a",NULL}`},
+		{[]sql.NullInt64{{Int64: 1, Valid: true}, {}}, `{1,NULL}`},
+		{[][]int{{1, 2}, {3, 4}}, `{{1,2},{3,4}}`},
+	}
+	for _, c := range cases {
+		value, err := Array(c.values).Value()
+		assert.NoError(t, err)
+		assert.Equal(t, value, c.expected)
+	}
+
+	_, err := Array([]struct{}{{}}).Value()
+	assert.EqualError(t, err, "goqu: Unable to encode array element struct {}")
+}
+
+func (me *arrayTest) TestScan() {
+	t := me.T()
+	var strs []string
+	assert.NoError(t, Array(&strs).Scan([]byte(`{a,"b\"c","d\\e","f,g", h i ,""}`)))
+	assert.Equal(t, strs, []string{"a", `b"c`, `d\e`, "f,g", "h i", ""})
+
+	assert.NoError(t, Array(&strs).Scan("{}"))
+	assert.Equal(t, strs, []string{})
+
+	assert.NoError(t, Array(&strs).Scan(nil))
+	assert.Nil(t, strs)
+
+	var ints []int64
+	assert.NoError(t, Array(&ints).Scan("[0:1]={1,2}"))
+	assert.Equal(t, ints, []int64{1, 2})
+
+	var ptrs []*string
+	assert.NoError(t, Array(&ptrs).Scan(`{a,NULL,"NULL"}`))
+	assert.Len(t, ptrs, 3)
+	assert.Equal(t, *ptrs[0], "a")
+	assert.Nil(t, ptrs[1])
+	assert.Equal(t, *ptrs[2], "NULL")
+
+	var bools []bool
+	assert.NoError(t, Array(&bools).Scan("{t,f}"))
+	assert.Equal(t, bools, []bool{true, false})
+
+	var floats []float64
+	assert.NoError(t, Array(&floats).Scan("{1.5,2}"))
+	assert.Equal(t, floats, []float64{1.5, 2})
+
+	var nulls []sql.NullInt64
+	assert.NoError(t, Array(&nulls).Scan("{1,NULL}"))
+	assert.Equal(t, nulls, []sql.NullInt64{{Int64: 1, Valid: true}, {}})
+
+	var matrix [][]int
+	assert.NoError(t, Array(&matrix).Scan("{{1,2},{3,4}}"))
+	assert.Equal(t, matrix, [][]int{{1, 2}, {3, 4}})
+
+	created := time.Date(2016, 1, 2, 3, 4, 5, 500000000, time.UTC)
+	value, err := Array([]time.Time{created}).Value()
+	assert.NoError(t, err)
+	var times []time.Time
+	assert.NoError(t, Array(&times).Scan(value))
+	assert.Equal(t, times, []time.Time{created})
+	var timePtrs []*time.Time
+	assert.NoError(t, Array(&timePtrs).Scan(`{"2016-01-02 03:04:05.5+00",NULL,2016-01-02}`))
+	assert.Len(t, timePtrs, 3)
+	assert.True(t, timePtrs[0].Equal(created))
+	assert.Nil(t, timePtrs[1])
+	assert.True(t, timePtrs[2].Equal(time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)))
+
+	assert.EqualError(t, Array(&ints).Scan("{1,NULL}"), "goqu: Unable to scan NULL array element into int64")
+	assert.EqualError(t, Array(&ints).Scan("{a}"), "goqu: Unable to scan array element a into int64")
+	assert.EqualError(t, Array(&times).Scan("{a}"), "goqu: Unable to scan array element a into time.Time")
+	assert.EqualError(t, Array(&ints).Scan("{1,2"), "goqu: Unable to parse array {1,2")
+	assert.EqualError(t, Array(&ints).Scan("1,2"), "goqu: Unable to parse array 1,2")
+	assert.EqualError(t, Array(&ints).Scan(1), "goqu: Unable to scan int into an array")
+	assert.EqualError(t, Array(ints).Scan("{1}"), "goqu: Array must be created with a pointer to a slice to be scanned")
+}
+
+func (me *arrayTest) TestScanVal() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT "tags" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"tags"}).AddRow([]byte(`{a,b}`)))
+	sqlmock.ExpectQuery(`SELECT "tags" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"tags"}).AddRow([]byte(`{a,b}`)).AddRow(nil))
+
+	db := New("mock", mDb)
+	var tags []string
+	found, err := db.From("items").Select("tags").ScanVal(Array(&tags))
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, tags, []string{"a", "b"})
+
+	var allTags [][]string
+	assert.NoError(t, db.From("items").Select("tags").ScanVals(&allTags))
+	assert.Equal(t, allTags, [][]string{{"a", "b"}, nil})
+}
+
+func TestArraySuite(t *testing.T) {
+	suite.Run(t, new(arrayTest))
+}
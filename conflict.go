@@ -0,0 +1,80 @@
+package goqu
+
+type (
+	//The action to take when an INSERT conflicts with an existing row. See DoNothing, DoUpdate and Dataset#OnConflict
+	ConflictAction interface {
+		//Returns true if the conflicting row should be left as is (e.g. DO NOTHING)
+		IsDoNothing() bool
+		//The updates to apply to the conflicting row, a map, Record or struct. See Dataset#ToUpdateSql
+		Updates() interface{}
+		//The condition the conflicting row must match to be updated, nil if there is none
+		Where() ExpressionList
+	}
+	conflictAction struct {
+		doNothing bool
+		updates   interface{}
+		where     ExpressionList
+	}
+
+	//A reference to a column of the row proposed for insertion, used within the updates of DoUpdate
+	//    Excluded("name") -> EXCLUDED."name" //postgres, sqlite3
+	//    Excluded("name") -> VALUES(`name`) //mysql
+	ExcludedExpression interface {
+		Expression
+		//The column of the proposed row
+		Col() IdentifierExpression
+	}
+	excluded struct {
+		col IdentifierExpression
+	}
+)
+
+//Creates a ConflictAction that leaves the conflicting row as is
+//    db.From("items").OnConflict("id", goqu.DoNothing()).Insert(item)
+func DoNothing() ConflictAction {
+	return conflictAction{doNothing: true}
+}
+
+//Creates a ConflictAction that updates the conflicting row
+//    db.From("items").OnConflict("id", goqu.DoUpdate(goqu.Record{"name": goqu.Excluded("name")})).Insert(item)
+//
+//updates: The updates to apply to the conflicting row, a map, Record or struct. See Dataset#ToUpdateSql
+//
+//where: Optional conditions the conflicting row must match to be updated, the conditions are ANDed together
+func DoUpdate(updates interface{}, where ...Expression) ConflictAction {
+	ret := conflictAction{updates: updates}
+	if len(where) > 0 {
+		ret.where = And(where...)
+	}
+	return ret
+}
+
+func (me conflictAction) IsDoNothing() bool {
+	return me.doNothing
+}
+
+func (me conflictAction) Updates() interface{} {
+	return me.updates
+}
+
+func (me conflictAction) Where() ExpressionList {
+	return me.where
+}
+
+//Creates a reference to a column of the row proposed for insertion, see DoUpdate
+//    goqu.DoUpdate(goqu.Record{"count": goqu.L("? + ?", goqu.I("items.count"), goqu.Excluded("count"))})
+func Excluded(col string) ExcludedExpression {
+	return excluded{col: I(col)}
+}
+
+func (me excluded) Clone() Expression {
+	return excluded{col: me.col.Clone().(IdentifierExpression)}
+}
+
+func (me excluded) Expression() Expression {
+	return me
+}
+
+func (me excluded) Col() IdentifierExpression {
+	return me.col
+}
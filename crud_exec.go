@@ -1,10 +1,15 @@
 package goqu
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 type (
@@ -12,29 +17,94 @@ type (
 		ColumnName string
 		Transient  bool
 		FieldName  string
-		GoType     reflect.Type
+		//the index path of the field for reflect.Value#FieldByIndex, it includes the index of any embedded structs
+		FieldIndex []int
+		//the index path of the pointer to a nested struct the field belongs to, nil if the field is not in a pointer
+		ParentIndex []int
+		GoType      reflect.Type
+		//set to true if the field is tagged with goqu:"json" and is decoded from a JSON column
+		Json bool
+		//set to true if the field is a slice that is scanned from an array column, see isArrayType
+		Array bool
 	}
 	columnMap map[string]columnData
 	CrudExec  struct {
-		database database
-		Sql      string
-		Args     []interface{}
-		err      error
+		database  database
+		Sql       string
+		Args      []interface{}
+		err       error
+		ctx       context.Context
+		prepared  bool
+		replica   bool
+		chunks    []*CrudExec
+		chunkInTx bool
+		noRowsErr bool
+		scanMode  ScanMode
 	}
 	selectResults []Record
+	//the sql.Result of a chunked insert, see Dataset#ChunkInserts
+	chunkedResult []sql.Result
 )
 
-var struct_map_cache = make(map[interface{}]columnMap)
+var (
+	time_type    = reflect.TypeOf(time.Time{})
+	scanner_type = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuer_type  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+//the columnMap of each struct type that has been scanned into, keyed by columnMapKey. A sync.Map is used so structs can be
+//scanned from multiple goroutines.
+var struct_map_cache sync.Map
+
+//used internally to cache the columnMap of a struct type for each NameMapper
+type columnMapKey struct {
+	structType reflect.Type
+	mapper     NameMapper
+}
 
 func newCrudExec(database database, err error, sql string, args ...interface{}) *CrudExec {
 	return &CrudExec{database: database, err: err, Sql: sql, Args: args}
 }
 
+//Returns the context the statement will be executed with. If no context has been set context.Background() is returned.
+func (me CrudExec) context() context.Context {
+	if me.ctx == nil {
+		return context.Background()
+	}
+	return me.ctx
+}
+
 func (me CrudExec) Exec() (sql.Result, error) {
+	return me.ExecContext(me.context())
+}
+
+//Same as Exec but the statement is aborted if the context is canceled or its deadline is exceeded.
+func (me CrudExec) ExecContext(ctx context.Context) (sql.Result, error) {
 	if me.err != nil {
 		return nil, me.err
 	}
-	return me.database.Exec(me.Sql, me.Args...)
+	if len(me.chunks) > 0 {
+		var results chunkedResult
+		err := me.eachChunk(ctx, func(i int, exec CrudExec) error {
+			if i == 0 {
+				results = results[0:0]
+			}
+			result, err := exec.ExecContext(ctx)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+	if me.prepared {
+		return me.database.preparedExecContext(ctx, me.Sql, me.Args...)
+	}
+	return me.database.ExecContext(ctx, me.Sql, me.Args...)
 }
 
 //This will execute the SQL and append results to the slice
@@ -47,6 +117,15 @@ func (me CrudExec) Exec() (sql.Result, error) {
 //
 //i: A pointer to a slice of structs.
 func (me CrudExec) ScanStructs(i interface{}) error {
+	return me.ScanStructsContext(me.context(), i)
+}
+
+//Same as ScanStructs but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: A pointer to a slice of structs.
+func (me CrudExec) ScanStructsContext(ctx context.Context, i interface{}) error {
 	if me.err != nil {
 		return me.err
 	}
@@ -57,7 +136,12 @@ func (me CrudExec) ScanStructs(i interface{}) error {
 	if reflect.Indirect(val).Kind() != reflect.Slice {
 		return NewGoquError("Type must be a pointer to a slice when calling ScanStructs")
 	}
-	_, err := me.scan(i, me.Sql, me.Args...)
+	if len(me.chunks) > 0 {
+		return me.scanChunks(ctx, reflect.Indirect(val), func(exec CrudExec) error {
+			return exec.ScanStructsContext(ctx, i)
+		})
+	}
+	_, err := me.scan(ctx, i)
 	return err
 }
 
@@ -73,6 +157,15 @@ func (me CrudExec) ScanStructs(i interface{}) error {
 //
 //i: A pointer to a struct
 func (me CrudExec) ScanStruct(i interface{}) (bool, error) {
+	return me.ScanStructContext(me.context(), i)
+}
+
+//Same as ScanStruct but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: A pointer to a struct
+func (me CrudExec) ScanStructContext(ctx context.Context, i interface{}) (bool, error) {
 	if me.err != nil {
 		return false, me.err
 	}
@@ -83,7 +176,14 @@ func (me CrudExec) ScanStruct(i interface{}) (bool, error) {
 	if reflect.Indirect(val).Kind() != reflect.Struct {
 		return false, NewGoquError("Type must be a pointer to a struct when calling ScanStruct")
 	}
-	return me.scan(i, me.Sql, me.Args...)
+	if len(me.chunks) > 0 {
+		return false, NewGoquError("Cannot call ScanStruct on a chunked insert, use ScanStructs")
+	}
+	found, err := me.scan(ctx, i)
+	if !found {
+		return me.notFound(err)
+	}
+	return true, nil
 }
 
 //This will execute the SQL and append results to the slice.
@@ -94,6 +194,15 @@ func (me CrudExec) ScanStruct(i interface{}) (bool, error) {
 //
 //i: Takes a pointer to a slice of primitive values.
 func (me CrudExec) ScanVals(i interface{}) error {
+	return me.ScanValsContext(me.context(), i)
+}
+
+//Same as ScanVals but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: Takes a pointer to a slice of primitive values.
+func (me CrudExec) ScanValsContext(ctx context.Context, i interface{}) error {
 	if me.err != nil {
 		return me.err
 	}
@@ -105,24 +214,29 @@ func (me CrudExec) ScanVals(i interface{}) error {
 	if val.Kind() != reflect.Slice {
 		return NewGoquError("Type must be a pointer to a slice when calling ScanVals")
 	}
+	if len(me.chunks) > 0 {
+		return me.scanChunks(ctx, val, func(exec CrudExec) error {
+			return exec.ScanValsContext(ctx, i)
+		})
+	}
 	t, _, isSliceOfPointers := getTypeInfo(i, val)
-	rows, err := me.database.Query(me.Sql, me.Args...)
+	scanner, err := me.database.scannerContext(ctx, me)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
-	for rows.Next() {
+	defer scanner.Close()
+	if isSliceOfPointers {
+		//scan into a pointer to a pointer so NULL values are appended as nil
+		t = reflect.PtrTo(t)
+	}
+	for scanner.Next() {
 		row := reflect.New(t)
-		if err := rows.Scan(row.Interface()); err != nil {
+		if err := scanner.ScanVal(row.Interface()); err != nil {
 			return err
 		}
-		if isSliceOfPointers {
-			val.Set(reflect.Append(val, row))
-		} else {
-			val.Set(reflect.Append(val, reflect.Indirect(row)))
-		}
+		val.Set(reflect.Append(val, reflect.Indirect(row)))
 	}
-	if err := rows.Err(); err != nil {
+	if err := scanner.Err(); err != nil {
 		return err
 	}
 	return nil
@@ -141,153 +255,519 @@ func (me CrudExec) ScanVals(i interface{}) error {
 //
 //   i: Takes a pointer to a primitive value.
 func (me CrudExec) ScanVal(i interface{}) (bool, error) {
+	return me.ScanValContext(me.context(), i)
+}
+
+//Same as ScanVal but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: Takes a pointer to a primitive value.
+func (me CrudExec) ScanValContext(ctx context.Context, i interface{}) (bool, error) {
 	if me.err != nil {
 		return false, me.err
 	}
 	val := reflect.ValueOf(i)
-	if val.Kind() != reflect.Ptr {
+	if val.Kind() != reflect.Ptr || val.IsNil() {
 		return false, NewGoquError("Type must be a pointer when calling ScanVal")
 	}
 	val = reflect.Indirect(val)
 	if val.Kind() == reflect.Slice {
 		return false, NewGoquError("Cannot scan into a slice when calling ScanVal")
 	}
-	rows, err := me.database.Query(me.Sql, me.Args...)
+	if len(me.chunks) > 0 {
+		return false, NewGoquError("Cannot call ScanVal on a chunked insert, use ScanVals")
+	}
+	scanner, err := me.database.scannerContext(ctx, me)
 	if err != nil {
 		return false, err
 	}
 	count := 0
-	defer rows.Close()
-	for rows.Next() {
+	defer scanner.Close()
+	for scanner.Next() {
 		count++
-		if err := rows.Scan(i); err != nil {
+		if err := scanner.ScanVal(i); err != nil {
 			return false, err
 		}
 	}
-	if err := rows.Err(); err != nil {
+	if err := scanner.Err(); err != nil {
 		return false, err
 	}
-	return count != 0, nil
+	if count == 0 {
+		return me.notFound(nil)
+	}
+	return true, nil
+}
+
+//This will execute the SQL and append a Record for each row to the slice, use this when the columns of the query are not
+//known at compile time. []byte values of textual columns are converted to strings, see Scanner#ScanRecord
+//    var records []goqu.Record
+//    if err := From("test").ScanMaps(&records); err != nil{
+//        panic(err.Error()
+//    }
+//
+//i: A pointer to a slice of Records
+func (me CrudExec) ScanMaps(i *[]Record) error {
+	return me.ScanMapsContext(me.context(), i)
 }
 
-func (me CrudExec) scan(i interface{}, query string, args ...interface{}) (bool, error) {
-	var (
-		found   bool
-		results []Record
-	)
-	cm, err := getColumnMap(i)
+//Same as ScanMaps but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: A pointer to a slice of Records
+func (me CrudExec) ScanMapsContext(ctx context.Context, i *[]Record) error {
+	if me.err != nil {
+		return me.err
+	}
+	if i == nil {
+		return NewGoquError("Type must be a pointer to a slice when calling ScanMaps")
+	}
+	if len(me.chunks) > 0 {
+		return me.scanChunks(ctx, reflect.ValueOf(i).Elem(), func(exec CrudExec) error {
+			return exec.ScanMapsContext(ctx, i)
+		})
+	}
+	scanner, err := me.database.scannerContext(ctx, me)
 	if err != nil {
-		return found, err
+		return err
+	}
+	defer scanner.Close()
+	for scanner.Next() {
+		record, err := scanner.ScanRecord()
+		if err != nil {
+			return err
+		}
+		*i = append(*i, record)
+	}
+	return scanner.Err()
+}
+
+//This will execute the SQL and set the Record to the first row. This method will return false if no row is found.
+//    var record goqu.Record
+//    found, err := From("test").Limit(1).ScanMap(&record)
+//    if err != nil{
+//        panic(err.Error()
+//    }
+//    if !found{
+//        fmt.Println("NOT FOUND")
+//    }
+//
+//i: A pointer to a Record
+func (me CrudExec) ScanMap(i *Record) (bool, error) {
+	return me.ScanMapContext(me.context(), i)
+}
+
+//Same as ScanMap but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: A pointer to a Record
+func (me CrudExec) ScanMapContext(ctx context.Context, i *Record) (bool, error) {
+	if me.err != nil {
+		return false, me.err
+	}
+	if i == nil {
+		return false, NewGoquError("Type must be a pointer when calling ScanMap")
 	}
-	rows, err := me.database.Query(query, args...)
+	if len(me.chunks) > 0 {
+		return false, NewGoquError("Cannot call ScanMap on a chunked insert, use ScanMaps")
+	}
+	scanner, err := me.database.scannerContext(ctx, me)
 	if err != nil {
 		return false, err
 	}
-	defer rows.Close()
-	columns, err := rows.Columns()
+	defer scanner.Close()
+	if !scanner.Next() {
+		return me.notFound(scanner.Err())
+	}
+	record, err := scanner.ScanRecord()
 	if err != nil {
 		return false, err
 	}
-	for rows.Next() {
-		scans := make([]interface{}, len(columns))
-		for i, col := range columns {
-			if data, ok := cm[col]; ok {
-				scans[i] = reflect.New(data.GoType).Interface()
-			} else {
-				return false, NewGoquError(`Unable to find corresponding field to column "%s" returned by query`, col)
+	*i = record
+	return true, nil
+}
+
+//This will execute the SQL and add each row to the map keyed by the value of the key column. If the values of the map are
+//slices (e.g. map[int64][]Item) the rows are grouped by key, otherwise the keys are expected to be unique and an error is
+//returned if a key is returned more than once.
+//    var items map[int64]Item
+//    if err := From("items").ScanStructsMap(&items, "id"); err != nil{
+//        panic(err.Error()
+//    }
+//
+//i: A pointer to a map of structs, pointers to structs or slices of either
+//
+//keyCol: The column to key the map by, it must map to a field of the struct
+func (me CrudExec) ScanStructsMap(i interface{}, keyCol string) error {
+	return me.ScanStructsMapContext(me.context(), i, keyCol)
+}
+
+//Same as ScanStructsMap but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: A pointer to a map of structs, pointers to structs or slices of either
+//
+//keyCol: The column to key the map by, it must map to a field of the struct
+func (me CrudExec) ScanStructsMapContext(ctx context.Context, i interface{}, keyCol string) error {
+	if me.err != nil {
+		return me.err
+	}
+	m, err := newScanMap(i, "ScanStructsMap")
+	if err != nil {
+		return err
+	}
+	if len(me.chunks) > 0 {
+		return NewGoquError("Cannot call ScanStructsMap on a chunked insert")
+	}
+	t, isPointer := m.elemType, false
+	if t.Kind() == reflect.Ptr {
+		t, isPointer = t.Elem(), true
+	}
+	if t.Kind() != reflect.Struct {
+		return NewGoquError("Type must be a pointer to a map of structs when calling ScanStructsMap")
+	}
+	cm, err := getColumnMap(reflect.New(t).Interface(), me.nameMapper())
+	if err != nil {
+		return err
+	}
+	keyData, ok := cm[keyCol]
+	if !ok || keyData.Transient {
+		return NewGoquError("Unable to find a struct field for key column %s", keyCol)
+	}
+	scanner, err := me.database.scannerContext(ctx, me)
+	if err != nil {
+		return err
+	}
+	defer scanner.Close()
+	for scanner.Next() {
+		row := reflect.New(t)
+		if err := scanner.scanStruct(row.Elem(), cm); err != nil {
+			return err
+		}
+		elem := row
+		if !isPointer {
+			elem = row.Elem()
+		}
+		key := fieldByIndex(row.Elem(), keyData.FieldIndex)
+		if !key.IsValid() {
+			return NewGoquError("Key column %s is NULL", keyCol)
+		}
+		if err := m.add(key, elem, keyCol); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+//This will execute the SQL and add the value of the second column of each row to the map keyed by the value of the first
+//column. If the values of the map are slices (e.g. map[int64][]string) the values are grouped by key, otherwise the keys
+//are expected to be unique and an error is returned if a key is returned more than once.
+//    var names map[int64]string
+//    if err := From("items").Select("id", "name").ScanValsMap(&names); err != nil{
+//        panic(err.Error()
+//    }
+//
+//i: A pointer to a map of primitive values or slices of primitive values
+func (me CrudExec) ScanValsMap(i interface{}) error {
+	return me.ScanValsMapContext(me.context(), i)
+}
+
+//Same as ScanValsMap but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: A pointer to a map of primitive values or slices of primitive values
+func (me CrudExec) ScanValsMapContext(ctx context.Context, i interface{}) error {
+	if me.err != nil {
+		return me.err
+	}
+	m, err := newScanMap(i, "ScanValsMap")
+	if err != nil {
+		return err
+	}
+	if len(me.chunks) > 0 {
+		return NewGoquError("Cannot call ScanValsMap on a chunked insert")
+	}
+	scanner, err := me.database.scannerContext(ctx, me)
+	if err != nil {
+		return err
+	}
+	defer scanner.Close()
+	columns, err := scanner.Columns()
+	if err != nil {
+		return err
+	}
+	if len(columns) != 2 {
+		return NewGoquError("Expected 2 columns when calling ScanValsMap got %d", len(columns))
+	}
+	for scanner.Next() {
+		key, val := reflect.New(m.keyType), reflect.New(m.elemType)
+		if err := scanner.scanVals(key.Interface(), val.Interface()); err != nil {
+			return err
+		}
+		if err := m.add(key.Elem(), val.Elem(), columns[0]); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+//This will execute the SQL and return a Scanner that can be used to iterate over the rows one at a time, the Scanner must be closed when done.
+//    scanner, err := From("test").Iter()
+//    if err != nil{
+//        panic(err.Error()
+//    }
+//    defer scanner.Close()
+//    for scanner.Next(){
+//        var myStruct MyStruct
+//        if err := scanner.ScanStruct(&myStruct); err != nil{
+//            panic(err.Error()
+//        }
+//    }
+//    if err := scanner.Err(); err != nil{
+//        panic(err.Error()
+//    }
+func (me CrudExec) Iter() (*Scanner, error) {
+	return me.IterContext(me.context())
+}
+
+//Same as Iter but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+func (me CrudExec) IterContext(ctx context.Context) (*Scanner, error) {
+	if me.err != nil {
+		return nil, me.err
+	}
+	if len(me.chunks) > 0 {
+		return nil, NewGoquError("Cannot call Iter on a chunked insert")
+	}
+	return me.database.scannerContext(ctx, me)
+}
+
+//used internally to call fn with each statement of a chunked insert in sequence, the statements are executed within a
+//transaction if requested. If the transaction is retried fn is called again starting with the first statement (i == 0).
+func (me CrudExec) eachChunk(ctx context.Context, fn func(i int, exec CrudExec) error) error {
+	run := func(db database) error {
+		for i, chunk := range me.chunks {
+			exec := *chunk
+			exec.database = db
+			if err := fn(i, exec); err != nil {
+				return err
 			}
 		}
-		if err := rows.Scan(scans...); err != nil {
+		return nil
+	}
+	if db, ok := me.database.(*Database); ok && me.chunkInTx {
+		return db.WithTxContext(ctx, nil, func(tx *TxDatabase) error {
+			return run(tx)
+		})
+	}
+	return run(me.database)
+}
+
+//used internally to scan the RETURNING rows of each statement of a chunked insert into the slice, if the transaction is
+//retried the rows scanned by the previous attempt are removed.
+func (me CrudExec) scanChunks(ctx context.Context, slice reflect.Value, scan func(exec CrudExec) error) error {
+	n := slice.Len()
+	return me.eachChunk(ctx, func(i int, exec CrudExec) error {
+		if i == 0 {
+			slice.SetLen(n)
+		}
+		return scan(exec)
+	})
+}
+
+//used internally to get the NameMapper of the database
+func (me CrudExec) nameMapper() NameMapper {
+	if me.database == nil {
+		return nil
+	}
+	return me.database.nameMapper()
+}
+
+//used internally to get the ScanMode set by the Dataset the CrudExec was created from, or the mode of the database if it
+//was not set
+func (me CrudExec) scanModeOr(mode ScanMode) ScanMode {
+	if me.scanMode != 0 {
+		return me.scanMode
+	}
+	return mode
+}
+
+//used internally to return the result of a single row action that did not find a row, ErrNoRows is returned if the
+//CrudExec was created from a Dataset with ErrOnNoRows
+func (me CrudExec) notFound(err error) (bool, error) {
+	if err == nil && me.noRowsErr {
+		err = ErrNoRows
+	}
+	return false, err
+}
+
+//used internally to scan the rows of a query directly into a struct or slice of structs, one row at a time.
+func (me CrudExec) scan(ctx context.Context, i interface{}) (bool, error) {
+	cm, err := getColumnMap(i, me.nameMapper())
+	if err != nil {
+		return false, err
+	}
+	scanner, err := me.database.scannerContext(ctx, me)
+	if err != nil {
+		return false, err
+	}
+	defer scanner.Close()
+	val := reflect.Indirect(reflect.ValueOf(i))
+	t, _, isSliceOfPointers := getTypeInfo(i, val)
+	found, results := false, val
+	for scanner.Next() {
+		row := reflect.New(t)
+		if err := scanner.scanStruct(row.Elem(), cm); err != nil {
 			return false, err
 		}
-		result := Record{}
-		for index, col := range columns {
-			result[col] = scans[index]
+		found = true
+		if val.Kind() == reflect.Struct {
+			val.Set(row.Elem())
+			break
+		}
+		if isSliceOfPointers {
+			results = reflect.Append(results, row)
+		} else {
+			results = reflect.Append(results, row.Elem())
 		}
-		results = append(results, result)
 	}
-	if rows.Err() != nil {
-		return false, rows.Err()
+	if err := scanner.Err(); err != nil {
+		return false, err
 	}
-	if len(results) > 0 {
-		found = true
-		return found, assignVals(i, results, cm)
+	if found && val.Kind() == reflect.Slice {
+		val.Set(results)
 	}
 	return found, nil
 }
 
-func assignVals(i interface{}, results []Record, cm columnMap) error {
-	val := reflect.Indirect(reflect.ValueOf(i))
-	t, _, isSliceOfPointers := getTypeInfo(i, val)
-	switch val.Kind() {
-	case reflect.Struct:
-		result := results[0]
-		for name, data := range cm {
-			src, ok := result[name]
-			if ok {
-				srcVal := reflect.ValueOf(src)
-				f := val.FieldByName(data.FieldName)
-				if f.Kind() == reflect.Ptr {
-					f.Set(reflect.ValueOf(srcVal))
-				} else {
-					f.Set(reflect.Indirect(srcVal))
-				}
-			}
+//Returns the LastInsertId of the last statement
+func (me chunkedResult) LastInsertId() (int64, error) {
+	return me[len(me)-1].LastInsertId()
+}
+
+//Returns the sum of the RowsAffected of each statement
+func (me chunkedResult) RowsAffected() (int64, error) {
+	var total int64
+	for _, result := range me {
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
 		}
-	case reflect.Slice:
-		for _, result := range results {
-			row := reflect.Indirect(reflect.New(t))
-			for name, data := range cm {
-				src, ok := result[name]
-				if ok {
-					srcVal := reflect.ValueOf(src)
-					f := row.FieldByName(data.FieldName)
-					if f.Kind() == reflect.Ptr {
-						f.Set(reflect.ValueOf(srcVal))
-					} else {
-						f.Set(reflect.Indirect(srcVal))
-					}
-				}
-			}
-			if isSliceOfPointers {
-				val.Set(reflect.Append(val, row.Addr()))
-			} else {
-				val.Set(reflect.Append(val, row))
-			}
+		total += n
+	}
+	return total, nil
+}
+
+//used internally to add the rows scanned by ScanStructsMap and ScanValsMap to a map
+type scanMap struct {
+	val      reflect.Value
+	keyType  reflect.Type
+	elemType reflect.Type
+	grouped  bool
+}
+
+//used internally to create a scanMap for a pointer to a map, a nil map is initialized
+func newScanMap(i interface{}, method string) (*scanMap, error) {
+	val := reflect.ValueOf(i)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Map {
+		return nil, NewGoquError("Type must be a pointer to a map when calling %s", method)
+	}
+	val = val.Elem()
+	if val.IsNil() {
+		val.Set(reflect.MakeMap(val.Type()))
+	}
+	m := &scanMap{val: val, keyType: val.Type().Key(), elemType: val.Type().Elem()}
+	if m.elemType.Kind() == reflect.Slice && m.elemType.Elem().Kind() != reflect.Uint8 {
+		m.elemType, m.grouped = m.elemType.Elem(), true
+	}
+	return m, nil
+}
+
+//used internally to add an element to the map, if the map is not grouped an error is returned for a duplicate key
+func (me *scanMap) add(key, elem reflect.Value, keyCol string) error {
+	if key.Type() != me.keyType {
+		if !key.Type().ConvertibleTo(me.keyType) {
+			return NewGoquError("Cannot use column %s of type %v as a key of type %v", keyCol, key.Type(), me.keyType)
+		}
+		key = key.Convert(me.keyType)
+	}
+	existing := me.val.MapIndex(key)
+	if me.grouped {
+		if !existing.IsValid() {
+			existing = reflect.MakeSlice(reflect.SliceOf(me.elemType), 0, 1)
 		}
+		me.val.SetMapIndex(key, reflect.Append(existing, elem))
+		return nil
+	}
+	if existing.IsValid() {
+		return NewGoquError("Duplicate key %v for column %s", key.Interface(), keyCol)
 	}
+	me.val.SetMapIndex(key, elem)
 	return nil
 }
 
-func getColumnMap(i interface{}) (columnMap, error) {
+//used internally to get the columnMap of a struct, or the struct of a slice, the columns of fields without a db tag are
+//named using the mapper, if the mapper is nil the lower case field name is used
+func getColumnMap(i interface{}, mapper NameMapper) (columnMap, error) {
 	val := reflect.Indirect(reflect.ValueOf(i))
 	t, valKind, _ := getTypeInfo(i, val)
 	if valKind != reflect.Struct {
 		return nil, NewGoquError(fmt.Sprintf("Cannot SELECT into this type: %v", t))
 	}
-	if _, ok := struct_map_cache[t]; !ok {
-		struct_map_cache[t] = createColumnMap(t)
+	if mapper != nil && !reflect.TypeOf(mapper).Comparable() {
+		//the mapper cannot be used as a key of the cache
+		return createColumnMap(t, nil, mapper), nil
 	}
-	return struct_map_cache[t], nil
+	key := columnMapKey{structType: t, mapper: mapper}
+	if cm, ok := struct_map_cache.Load(key); ok {
+		return cm.(columnMap), nil
+	}
+	//the column map of a type is always the same so it does not matter which goroutine stores it
+	cm, _ := struct_map_cache.LoadOrStore(key, createColumnMap(t, nil, mapper))
+	return cm.(columnMap), nil
 }
 
-func createColumnMap(t reflect.Type) columnMap {
+//used internally to create the columnMap of a struct type, index is the index path of the struct within the struct
+//being scanned into
+func createColumnMap(t reflect.Type, index []int, mapper NameMapper) columnMap {
 	cm, n := columnMap{}, t.NumField()
 	var subColMaps []columnMap
 	for i := 0; i < n; i++ {
 		f := t.Field(i)
-		if f.Anonymous && f.Type.Kind() == reflect.Struct {
-			subColMaps = append(subColMaps, createColumnMap(f.Type))
-		} else {
-			columnName := f.Tag.Get("db")
-			if columnName == "" {
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+		columnName := f.Tag.Get("db")
+		if columnName == "" {
+			if mapper == nil {
 				columnName = strings.ToLower(f.Name)
+			} else {
+				columnName = mapper.ColumnName(f.Name)
+			}
+		}
+		jsonField := isJsonField(f)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && !jsonField {
+			subColMaps = append(subColMaps, createColumnMap(f.Type, fieldIndex, mapper))
+		} else if nested := nestedStructType(f.Type); nested != nil && columnName != "-" && !jsonField {
+			//the columns of a nested struct are prefixed with the column name of the field (e.g. "user.id")
+			for key, data := range createColumnMap(nested, fieldIndex, mapper) {
+				if f.Type.Kind() == reflect.Ptr {
+					data.ParentIndex = fieldIndex
+				}
+				cm[columnName+"."+key] = data
 			}
+		} else {
 			cm[columnName] = columnData{
 				ColumnName: columnName,
 				Transient:  columnName == "-",
 				FieldName:  f.Name,
+				FieldIndex: fieldIndex,
 				GoType:     f.Type,
+				Json:       jsonField,
+				Array:      !jsonField && isArrayType(f.Type),
 			}
 		}
 	}
@@ -301,6 +781,70 @@ func createColumnMap(t reflect.Type) columnMap {
 	return cm
 }
 
+//used internally to get the struct type of a named struct or pointer to struct field whose columns are scanned from
+//prefixed columns, nil is returned if the field is scanned as a single column (e.g. time.Time or a sql.Scanner)
+func nestedStructType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == time_type {
+		return nil
+	}
+	if reflect.PtrTo(t).Implements(scanner_type) || t.Implements(valuer_type) {
+		return nil
+	}
+	return t
+}
+
+//used internally to get the columns of a columnMap that can be selected, ordered by the fields of the struct
+func (me columnMap) selectCols() []string {
+	var cols []string
+	for col, data := range me {
+		if !data.Transient {
+			cols = append(cols, col)
+		}
+	}
+	sort.Slice(cols, func(i, j int) bool {
+		a, b := me[cols[i]].FieldIndex, me[cols[j]].FieldIndex
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return cols
+}
+
+//used internally to get the field of a struct by its index path, an invalid reflect.Value is returned if the path
+//contains a nil pointer
+func fieldByIndex(val reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				return reflect.Value{}
+			}
+			val = val.Elem()
+		}
+		val = val.Field(i)
+	}
+	return val
+}
+
+//used internally to get the field of a struct by its index path, any nil pointers in the path are allocated
+func fieldByIndexAlloc(val reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				val.Set(reflect.New(val.Type().Elem()))
+			}
+			val = val.Elem()
+		}
+		val = val.Field(i)
+	}
+	return val
+}
+
 func getTypeInfo(i interface{}, val reflect.Value) (reflect.Type, reflect.Kind, bool) {
 	var t reflect.Type
 	isSliceOfPointers := false
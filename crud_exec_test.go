@@ -1,10 +1,13 @@
 package goqu
 
 import (
+	"context"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -24,6 +27,17 @@ type testComposedCrudActionItem struct {
 	Age         int64  `db:"age"`
 }
 
+type testNestedCrudActionItem struct {
+	Item testCrudActionItem  `db:"items"`
+	User *testCrudActionItem `db:"users"`
+}
+
+type testStrictCrudActionItem struct {
+	Address string              `db:"address"`
+	User    *testCrudActionItem `db:"users"`
+	name    string
+}
+
 type crudExecTest struct {
 	suite.Suite
 }
@@ -196,6 +210,108 @@ func (me *crudExecTest) TestScanStruct() {
 	assert.Equal(t, noTag.Name, "Test1")
 }
 
+func (me *crudExecTest) TestScanStructs_Nested() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"items.address", "items.name", "users.address", "users.name"}).
+			AddRow("111 Test Addr", "Test1", "211 Test Addr", "Bob").
+			AddRow("112 Test Addr", "Test2", nil, nil))
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"items.address", "items.name", "users.address", "name"}).
+			AddRow("111 Test Addr", "Test1", "211 Test Addr", "Bob"))
+
+	db := New("db-mock", mDb)
+	exec := newCrudExec(db, nil, `SELECT * FROM "items"`)
+
+	var items []testNestedCrudActionItem
+	assert.NoError(t, exec.ScanStructs(&items))
+	assert.Equal(t, items, []testNestedCrudActionItem{
+		{
+			Item: testCrudActionItem{Address: "111 Test Addr", Name: "Test1"},
+			User: &testCrudActionItem{Address: "211 Test Addr", Name: "Bob"},
+		},
+		{Item: testCrudActionItem{Address: "112 Test Addr", Name: "Test2"}},
+	})
+
+	var item testNestedCrudActionItem
+	found, err := exec.ScanStruct(&item)
+	assert.EqualError(t, err, `goqu: Unable to find corresponding field to column "name" returned by query`)
+	assert.False(t, found)
+}
+
+func (me *crudExecTest) TestScanStructs_ScanMode() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name", "created"}).FromCSVString("111 Test Addr,Test1,2016-01-01"))
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name", "created"}).FromCSVString("111 Test Addr,Test1,2016-01-01"))
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1"))
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address"}).FromCSVString("111 Test Addr"))
+
+	db := New("db-mock", mDb)
+	var unmapped [][]string
+	db.Use(HookFunc(func(ctx context.Context, event *QueryEvent) {
+		unmapped = append(unmapped, event.UnmappedColumns)
+	}))
+	exec := newCrudExec(db, nil, `SELECT * FROM "items"`)
+
+	var items []testCrudActionItem
+	assert.EqualError(t, exec.ScanStructs(&items), `goqu: Unable to find corresponding field to column "created" returned by query`)
+
+	db.ScanMode(SCAN_LENIENT)
+	assert.NoError(t, exec.ScanStructs(&items))
+	assert.Equal(t, items, []testCrudActionItem{{Address: "111 Test Addr", Name: "Test1"}})
+
+	db.ScanMode(SCAN_STRICT)
+	items = nil
+	assert.NoError(t, exec.ScanStructs(&items))
+	assert.Equal(t, items, []testCrudActionItem{{Address: "111 Test Addr", Name: "Test1"}})
+	assert.EqualError(t, exec.ScanStructs(&items), `goqu: Unable to find corresponding column "name" for field Name in the columns returned by query`)
+	assert.Equal(t, unmapped, [][]string{nil, {"created"}, nil, nil})
+}
+
+func (me *crudExecTest) TestScanStructs_ScanModeStrictOptionalFields() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address"}).FromCSVString("111 Test Addr"))
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"users.name"}).FromCSVString("Bob"))
+
+	db := New("db-mock", mDb)
+	db.ScanMode(SCAN_STRICT)
+	exec := newCrudExec(db, nil, `SELECT * FROM "items"`)
+
+	//the unexported field and the fields of the pointer to a nested struct are not required
+	var items []testStrictCrudActionItem
+	assert.NoError(t, exec.ScanStructs(&items))
+	assert.Equal(t, items, []testStrictCrudActionItem{{Address: "111 Test Addr"}})
+	assert.EqualError(t, exec.ScanStructs(&items), `goqu: Unable to find corresponding column "address" for field Address in the columns returned by query`)
+}
+
 func (me *crudExecTest) TestScanVals() {
 	t := me.T()
 	mDb, err := sqlmock.New()
@@ -231,6 +347,25 @@ func (me *crudExecTest) TestScanVals() {
 	assert.Equal(t, *pointers[1], 2)
 }
 
+func (me *crudExecTest) TestScanVals_NullPointers() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlmock.ExpectQuery(`SELECT "id" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(nil))
+
+	db := New("db-mock", mDb)
+	exec := newCrudExec(db, nil, `SELECT "id" FROM "items"`)
+
+	var pointers []*int64
+	assert.NoError(t, exec.ScanVals(&pointers))
+	assert.Len(t, pointers, 2)
+	assert.Equal(t, *pointers[0], int64(1))
+	assert.Nil(t, pointers[1])
+}
+
 func (me *crudExecTest) TestScanVal() {
 	t := me.T()
 	mDb, err := sqlmock.New()
@@ -251,6 +386,9 @@ func (me *crudExecTest) TestScanVal() {
 	found, err := exec.ScanVal(id)
 	assert.EqualError(t, err, "goqu: Type must be a pointer when calling ScanVal")
 	assert.False(t, found)
+	found, err = exec.ScanVal((*int64)(nil))
+	assert.EqualError(t, err, "goqu: Type must be a pointer when calling ScanVal")
+	assert.False(t, found)
 	found, err = exec.ScanVal(&ids)
 	assert.EqualError(t, err, "goqu: Cannot scan into a slice when calling ScanVal")
 	assert.False(t, found)
@@ -264,6 +402,341 @@ func (me *crudExecTest) TestScanVal() {
 	assert.Equal(t, ptrId, 1)
 }
 
+func (me *crudExecTest) TestScanMaps() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WillReturnError(fmt.Errorf("query error"))
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Test1").AddRow(int64(2), nil))
+
+	db := New("db-mock", mDb)
+	exec := newCrudExec(db, nil, `SELECT * FROM "items"`)
+
+	var records []Record
+	assert.EqualError(t, exec.ScanMaps(nil), "goqu: Type must be a pointer to a slice when calling ScanMaps")
+	assert.EqualError(t, exec.ScanMaps(&records), "query error")
+
+	assert.NoError(t, exec.ScanMaps(&records))
+	assert.Equal(t, records, []Record{
+		{"id": int64(1), "name": "Test1"},
+		{"id": int64(2), "name": nil},
+	})
+	assert.EqualError(t, newCrudExec(db, fmt.Errorf("crud exec error"), "").ScanMaps(&records), "crud exec error")
+}
+
+func (me *crudExecTest) TestScanMap() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Test1").AddRow(int64(2), "Test2"))
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	db := New("db-mock", mDb)
+	exec := newCrudExec(db, nil, `SELECT * FROM "items"`)
+
+	var record Record
+	found, err := exec.ScanMap(nil)
+	assert.EqualError(t, err, "goqu: Type must be a pointer when calling ScanMap")
+	assert.False(t, found)
+
+	found, err = exec.ScanMap(&record)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, record, Record{"id": int64(1), "name": "Test1"})
+
+	record = nil
+	found, err = exec.ScanMap(&record)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Nil(t, record)
+}
+
+func (me *crudExecTest) TestScanStructsMap() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1\n211 Test Addr,Test2"))
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name", "phone_number", "age"}).FromCSVString("111 Test Addr,Test1,111-111-1111,20\n211 Test Addr,Test2,222-222-2222,20\n311 Test Addr,Test3,333-333-3333,30"))
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1\n111 Test Addr,Test2"))
+
+	db := New("db-mock", mDb)
+	exec := newCrudExec(db, nil, `SELECT * FROM "items"`)
+
+	var items map[string]testCrudActionItem
+	assert.EqualError(t, exec.ScanStructsMap(items, "name"), "goqu: Type must be a pointer to a map when calling ScanStructsMap")
+	assert.EqualError(t, exec.ScanStructsMap(&[]testCrudActionItem{}, "name"), "goqu: Type must be a pointer to a map when calling ScanStructsMap")
+	assert.EqualError(t, exec.ScanStructsMap(&map[string]string{}, "name"), "goqu: Type must be a pointer to a map of structs when calling ScanStructsMap")
+	assert.EqualError(t, exec.ScanStructsMap(&items, "id"), "goqu: Unable to find a struct field for key column id")
+
+	assert.NoError(t, exec.ScanStructsMap(&items, "name"))
+	assert.Equal(t, items, map[string]testCrudActionItem{
+		"Test1": {Address: "111 Test Addr", Name: "Test1"},
+		"Test2": {Address: "211 Test Addr", Name: "Test2"},
+	})
+
+	var grouped map[int64][]*testComposedCrudActionItem
+	assert.NoError(t, exec.ScanStructsMap(&grouped, "age"))
+	assert.Len(t, grouped, 2)
+	assert.Len(t, grouped[20], 2)
+	assert.Equal(t, grouped[20][0].Name, "Test1")
+	assert.Equal(t, grouped[20][1].Name, "Test2")
+	assert.Len(t, grouped[30], 1)
+	assert.Equal(t, grouped[30][0].PhoneNumber, "333-333-3333")
+
+	items = nil
+	assert.EqualError(t, exec.ScanStructsMap(&items, "address"), "goqu: Duplicate key 111 Test Addr for column address")
+}
+
+func (me *crudExecTest) TestScanValsMap() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlmock.ExpectQuery(`SELECT "id", "name" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).FromCSVString("1,Test1\n2,Test2"))
+
+	sqlmock.ExpectQuery(`SELECT "id", "name" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).FromCSVString("1,Test1\n1,Test2\n2,Test3"))
+
+	sqlmock.ExpectQuery(`SELECT "id", "name" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).FromCSVString("1,Test1\n1,Test2"))
+
+	sqlmock.ExpectQuery(`SELECT "id", "name" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).FromCSVString("1"))
+
+	db := New("db-mock", mDb)
+	exec := newCrudExec(db, nil, `SELECT "id", "name" FROM "items"`)
+
+	var names map[int64]string
+	assert.EqualError(t, exec.ScanValsMap(names), "goqu: Type must be a pointer to a map when calling ScanValsMap")
+	assert.NoError(t, exec.ScanValsMap(&names))
+	assert.Equal(t, names, map[int64]string{1: "Test1", 2: "Test2"})
+
+	var grouped map[int64][]string
+	assert.NoError(t, exec.ScanValsMap(&grouped))
+	assert.Equal(t, grouped, map[int64][]string{1: {"Test1", "Test2"}, 2: {"Test3"}})
+
+	names = nil
+	assert.EqualError(t, exec.ScanValsMap(&names), "goqu: Duplicate key 1 for column id")
+	assert.EqualError(t, exec.ScanValsMap(&names), "goqu: Expected 2 columns when calling ScanValsMap got 1")
+}
+
+func (me *crudExecTest) TestContext() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlmock.ExpectQuery(`SELECT "id" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).FromCSVString("1\n2"))
+
+	db := New("db-mock", mDb)
+	exec := newCrudExec(db, nil, `SELECT "id" FROM "items"`)
+
+	var ids []int64
+	assert.NoError(t, exec.ScanValsContext(context.Background(), &ids))
+	assert.Equal(t, ids, []int64{1, 2})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = exec.ExecContext(ctx)
+	assert.EqualError(t, err, "context canceled")
+	var items []testCrudActionItem
+	assert.EqualError(t, exec.ScanStructsContext(ctx, &items), "context canceled")
+	found, err := exec.ScanStructContext(ctx, &testCrudActionItem{})
+	assert.EqualError(t, err, "context canceled")
+	assert.False(t, found)
+	assert.EqualError(t, exec.ScanValsContext(ctx, &ids), "context canceled")
+	var id int64
+	found, err = exec.ScanValContext(ctx, &id)
+	assert.EqualError(t, err, "context canceled")
+	assert.False(t, found)
+
+	exec.ctx = ctx
+	_, err = exec.Exec()
+	assert.EqualError(t, err, "context canceled")
+	assert.EqualError(t, exec.ScanVals(&ids), "context canceled")
+}
+
+func (me *crudExecTest) TestGetColumnMap() {
+	t := me.T()
+	cm, err := getColumnMap(&testComposedCrudActionItem{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, cm["address"].FieldIndex, []int{0, 0})
+	assert.Equal(t, cm["name"].FieldIndex, []int{0, 1})
+	assert.Equal(t, cm["phone_number"].FieldIndex, []int{1})
+	assert.Equal(t, cm["age"].FieldIndex, []int{2})
+
+	var items []testComposedCrudActionItem
+	sliceCm, err := getColumnMap(&items, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sliceCm, cm)
+
+	_, err = getColumnMap(&[]string{}, nil)
+	assert.EqualError(t, err, "goqu: Cannot SELECT into this type: string")
+}
+
+func (me *crudExecTest) TestScanStructs_Json() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	type address struct {
+		City string `json:"city"`
+	}
+	type owner struct {
+		Name string   `db:"name"`
+		Tags []string `db:"tags" goqu:"json"`
+	}
+	type item struct {
+		Id      int64    `db:"id"`
+		Tags    []string `db:"tags" goqu:"json"`
+		Address address  `db:"address" goqu:"json"`
+		Prev    *address `db:"prev" goqu:"json"`
+		Owner   *owner   `db:"owner"`
+	}
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tags", "address", "prev", "owner.name", "owner.tags"}).
+			AddRow(1, []byte(`["a","b"]`), `{"city":"Boise"}`, nil, "Test1", []byte(`["c"]`)).
+			AddRow(2, nil, `{}`, `{"city":"Reno"}`, nil, nil))
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tags"}).AddRow(1, "not json"))
+
+	db := New("db-mock", mDb)
+	var items []item
+	assert.NoError(t, newCrudExec(db, nil, `SELECT * FROM "items"`).ScanStructs(&items))
+	assert.Equal(t, items, []item{
+		{Id: 1, Tags: []string{"a", "b"}, Address: address{City: "Boise"}, Owner: &owner{Name: "Test1", Tags: []string{"c"}}},
+		{Id: 2, Prev: &address{City: "Reno"}},
+	})
+
+	items = nil
+	err = newCrudExec(db, nil, `SELECT * FROM "items"`).ScanStructs(&items)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "goqu: Unable to decode JSON value: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func (me *crudExecTest) TestScanStructs_Array() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	type owner struct {
+		Ids []int64 `db:"ids"`
+	}
+	type item struct {
+		Id    int64    `db:"id"`
+		Tags  []string `db:"tags"`
+		Owner *owner   `db:"owner"`
+	}
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tags", "owner.ids"}).
+			AddRow(1, []byte(`{a,"b c"}`), []byte(`{1,2}`)).
+			AddRow(2, nil, nil))
+
+	db := New("db-mock", mDb)
+	var items []item
+	assert.NoError(t, newCrudExec(db, nil, `SELECT * FROM "items"`).ScanStructs(&items))
+	assert.Equal(t, items, []item{
+		{Id: 1, Tags: []string{"a", "b c"}, Owner: &owner{Ids: []int64{1, 2}}},
+		{Id: 2},
+	})
+}
+
+func (me *crudExecTest) TestScanStructs_NameMapper() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	type item struct {
+		HomeAddress string
+		Name        string `db:"item_name"`
+	}
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"home_address", "item_name"}).FromCSVString("111 Test Addr,Test1"))
+
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"home_address", "item_name"}).FromCSVString("211 Test Addr,Test2"))
+	sqlmock.ExpectCommit()
+
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"homeaddress", "item_name"}).FromCSVString("311 Test Addr,Test3"))
+
+	db := New("db-mock", mDb)
+	db.NameMapper(SnakeCaseMapper())
+	var items []item
+	assert.NoError(t, newCrudExec(db, nil, `SELECT * FROM "items"`).ScanStructs(&items))
+	assert.Equal(t, items, []item{{HomeAddress: "111 Test Addr", Name: "Test1"}})
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.ScanStructs(&items, `SELECT * FROM "items"`))
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, items, []item{{HomeAddress: "111 Test Addr", Name: "Test1"}, {HomeAddress: "211 Test Addr", Name: "Test2"}})
+
+	db.NameMapper(nil)
+	items = nil
+	assert.NoError(t, newCrudExec(db, nil, `SELECT * FROM "items"`).ScanStructs(&items))
+	assert.Equal(t, items, []item{{HomeAddress: "311 Test Addr", Name: "Test3"}})
+}
+
+func (me *crudExecTest) TestGetColumnMap_Concurrent() {
+	t := me.T()
+	type concurrentItem struct {
+		testCrudActionItem
+		Id int64 `db:"id"`
+	}
+	var wg sync.WaitGroup
+	cms := make([]columnMap, 50)
+	for i := range cms {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cms[i], _ = getColumnMap(&concurrentItem{}, nil)
+		}(i)
+	}
+	wg.Wait()
+	for _, cm := range cms {
+		assert.Equal(t, cm["id"].FieldIndex, []int{1})
+		assert.Equal(t, cm["id"].GoType, reflect.TypeOf(int64(0)))
+	}
+	cached, ok := struct_map_cache.Load(columnMapKey{structType: reflect.TypeOf(concurrentItem{})})
+	assert.True(t, ok)
+	assert.Equal(t, cached, cms[0])
+}
+
 func TestCrudExecSuite(t *testing.T) {
 	suite.Run(t, new(crudExecTest))
 }
@@ -1,6 +1,11 @@
 package goqu
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
 
 type (
 	database interface {
@@ -8,22 +13,72 @@ type (
 		From(cols ...interface{}) *Dataset
 		Logger(logger Logger)
 		Exec(query string, args ...interface{}) (sql.Result, error)
+		ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 		Prepare(query string) (*sql.Stmt, error)
+		PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
 		Query(query string, args ...interface{}) (*sql.Rows, error)
+		QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 		QueryRow(query string, args ...interface{}) *sql.Row
+		QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 		ScanStructs(i interface{}, query string, args ...interface{}) error
+		ScanStructsContext(ctx context.Context, i interface{}, query string, args ...interface{}) error
 		ScanStruct(i interface{}, query string, args ...interface{}) (bool, error)
+		ScanStructContext(ctx context.Context, i interface{}, query string, args ...interface{}) (bool, error)
 		ScanVals(i interface{}, query string, args ...interface{}) error
+		ScanValsContext(ctx context.Context, i interface{}, query string, args ...interface{}) error
 		ScanVal(i interface{}, query string, args ...interface{}) (bool, error)
+		ScanValContext(ctx context.Context, i interface{}, query string, args ...interface{}) (bool, error)
+		ScanMaps(i *[]Record, query string, args ...interface{}) error
+		ScanMapsContext(ctx context.Context, i *[]Record, query string, args ...interface{}) error
+		ScanMap(i *Record, query string, args ...interface{}) (bool, error)
+		ScanMapContext(ctx context.Context, i *Record, query string, args ...interface{}) (bool, error)
+		preparedExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+		scannerContext(ctx context.Context, exec CrudExec) (*Scanner, error)
+		nameMapper() NameMapper
 	}
 	//This struct is the wrapper for a Db. The struct delegates most calls to either an Exec instance or to the Db passed into the constructor.
 	Database struct {
-		logger  Logger
-		Dialect string
-		Db      *sql.DB
+		logger            Logger
+		hooks             hookChain
+		txRetryPolicy     *TxRetryPolicy
+		stmtCache         *stmtCache
+		replicas          []*sql.DB
+		replicaStmtCaches []*stmtCache
+		balancer          ReplicaBalancer
+		scanMode          ScanMode
+		mapper            NameMapper
+		Dialect           string
+		Db                *sql.DB
+	}
+	//Configures how Database#WithTx retries a transaction that failed with a serialization failure or deadlock.
+	TxRetryPolicy struct {
+		//The maximum number of times a transaction will be retried (DEFAULT=3)
+		MaxRetries int
+		//Returns how long to wait before the given retry attempt, starting at 1. If nil the transaction is retried immediately.
+		//(DEFAULT=ExponentialBackoff(10*time.Millisecond, time.Second))
+		Backoff func(attempt int) time.Duration
 	}
 )
 
+var default_tx_retry_policy = TxRetryPolicy{
+	MaxRetries: 3,
+	Backoff:    ExponentialBackoff(10*time.Millisecond, time.Second),
+}
+
+//Returns a backoff function for a TxRetryPolicy that doubles the wait time for each attempt starting at base, never waiting longer than max.
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		wait := base
+		for i := 1; i < attempt && wait < max; i++ {
+			wait *= 2
+		}
+		if wait > max {
+			return max
+		}
+		return wait
+	}
+}
+
 //This is the common entry point into goqu.
 //
 //dialect: This is the adapter dialect, you should see your database adapter for the string to use. Built in adpaters can be found at https://github.com/doug-martin/goqu/tree/master/adapters
@@ -54,13 +109,135 @@ func New(dialect string, db *sql.DB) *Database {
 	return &Database{Dialect: dialect, Db: db}
 }
 
+//Creates a Database that routes the read actions of a Dataset (e.g. ScanStructs, ScanVals, Count, Pluck) to the replicas,
+//all other statements, including statements executed within a transaction, are executed using the primary.
+//Use Dataset#UsePrimary to read from the primary, e.g. when reading your own writes.
+//      db := goqu.NewWithReplicas("postgres", primaryDb, replicaDb1, replicaDb2)
+//      //executed using one of the replicas
+//      count, err := db.From("items").Count()
+//      //executed using the primary
+//      _, err = db.From("items").Insert(goqu.Record{"name": "Test"}).Exec()
+//
+//dialect: This is the adapter dialect, see New
+//
+//primary: The sql.Db to write to
+//
+//replicas...: The sql.Db for each replica, reads are balanced between them using round robin, see ReplicaBalancer
+func NewWithReplicas(dialect string, primary *sql.DB, replicas ...*sql.DB) *Database {
+	return &Database{Dialect: dialect, Db: primary, replicas: replicas, balancer: RoundRobinBalancer()}
+}
+
+//Sets the balancer used to choose the replica a read is routed to (DEFAULT=RoundRobinBalancer()). A nil balancer
+//resets it to RoundRobinBalancer()
+func (me *Database) ReplicaBalancer(balancer ReplicaBalancer) {
+	if balancer == nil {
+		balancer = RoundRobinBalancer()
+	}
+	me.balancer = balancer
+}
+
 //Starts a new Transaction.
 func (me *Database) Begin() (*TxDatabase, error) {
-	tx, err := me.Db.Begin()
+	return me.BeginTx(context.Background(), nil)
+}
+
+//Starts a new Transaction with the given context and options.
+//
+//ctx: The context used for the lifetime of the transaction, if the context is canceled the transaction is rolled back.
+//
+//opts: The isolation level and read only mode of the transaction, if nil the drivers default is used.
+//      tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true})
+func (me *Database) BeginTx(ctx context.Context, opts *sql.TxOptions) (*TxDatabase, error) {
+	tx, err := me.Db.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
-	return &TxDatabase{Dialect: me.Dialect, Tx: tx, logger: me.logger}, nil
+	return &TxDatabase{Dialect: me.Dialect, Tx: tx, ctx: ctx, logger: me.logger, hooks: me.hooks, stmtCache: me.stmtCache, scanMode: me.scanMode, mapper: me.mapper}, nil
+}
+
+//Sets the policy used by WithTx when retrying transactions that failed with a serialization failure or deadlock.
+func (me *Database) TxRetryPolicy(policy TxRetryPolicy) {
+	me.txRetryPolicy = &policy
+}
+
+//Enables a cache of prepared statements that is used when executing a prepared Dataset (See Dataset#Prepared). Statements
+//are cached by their sql, once the cache holds size statements the least recently used statement is closed and evicted.
+//Transactions started from the database re-use the cached statements by binding them to the transaction (See sql.Tx#Stmt).
+//
+//Calling StatementCache again replaces the cache and closes all cached statements.
+//      db.StatementCache(100)
+//      //the first call prepares the statement, the following calls re-use it
+//      found, err := db.From("items").Prepared(true).Where(goqu.I("id").Eq(id)).ScanStruct(&item)
+//
+//size: The maximum number of statements to cache, if less than or equal to 0 the cache is disabled.
+func (me *Database) StatementCache(size int) {
+	if me.stmtCache != nil {
+		me.stmtCache.clear()
+		for _, cache := range me.replicaStmtCaches {
+			cache.clear()
+		}
+		me.stmtCache, me.replicaStmtCaches = nil, nil
+	}
+	if size > 0 {
+		me.stmtCache = newStmtCache(me.PrepareContext, size)
+		for _, replica := range me.replicas {
+			me.replicaStmtCaches = append(me.replicaStmtCaches, newStmtCache(me.preparer(replica), size))
+		}
+	}
+}
+
+//Runs the function in a new transaction that is committed if the function returns nil or rolled back otherwise.
+//If the function or COMMIT fails with an error the adapter classifies as a serialization failure or deadlock (See Adapter#IsRetryableTxError)
+//the transaction is retried according to the TxRetryPolicy of the database.
+//
+//NOTE: The function may be called more than once so it should not have side effects outside of the transaction.
+//      err := db.WithTx(&sql.TxOptions{Isolation: sql.LevelSerializable}, func(tx *goqu.TxDatabase) error {
+//          _, err := tx.From("items").Where(goqu.I("id").Eq(1)).Update(goqu.Record{"name": "Bob"}).Exec()
+//          return err
+//      })
+//
+//opts: The isolation level and read only mode of the transaction, if nil the drivers default is used.
+//
+//fn: The function to run within the transaction
+func (me *Database) WithTx(opts *sql.TxOptions, fn func(tx *TxDatabase) error) error {
+	return me.WithTxContext(context.Background(), opts, fn)
+}
+
+//Same as WithTx but the transaction is started with the context. If the context is canceled while waiting to retry the
+//context error is returned.
+func (me *Database) WithTxContext(ctx context.Context, opts *sql.TxOptions, fn func(tx *TxDatabase) error) error {
+	policy := default_tx_retry_policy
+	if me.txRetryPolicy != nil {
+		policy = *me.txRetryPolicy
+	}
+	adapter := me.queryAdapter(nil)
+	for attempt := 1; ; attempt++ {
+		err := me.runTx(ctx, opts, fn)
+		if err == nil || attempt > policy.MaxRetries || !adapter.IsRetryableTxError(driverError(err)) {
+			return err
+		}
+		me.Trace("RETRY", "")
+		if policy.Backoff != nil {
+			timer := time.NewTimer(policy.Backoff(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+//used internally to run a single attempt of a WithTx function
+func (me *Database) runTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *TxDatabase) error) error {
+	tx, err := me.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return tx.Wrap(func() error {
+		return fn(tx)
+	})
 }
 
 //used internally to create a new Adapter for a dataset
@@ -85,6 +262,53 @@ func (me *Database) Logger(logger Logger) {
 	me.logger = logger
 }
 
+//Sets how the columns returned by a query are matched to the fields of a struct when scanning, transactions started from
+//the database inherit the ScanMode. See ScanMode (DEFAULT=SCAN_DEFAULT)
+//    db.ScanMode(goqu.SCAN_LENIENT)
+func (me *Database) ScanMode(mode ScanMode) {
+	me.scanMode = mode
+}
+
+//Sets the NameMapper used to map the names of struct fields that do not have a db tag to column names when scanning,
+//inserting and updating structs, transactions started from the database inherit the NameMapper. By default the lower case
+//field name is used when scanning and fields without a db tag are not inserted or updated.
+//    db.NameMapper(goqu.SnakeCaseMapper())
+//    type Item struct {
+//        Id        int64
+//        CreatedAt time.Time
+//    }
+//    //INSERT INTO "items" ("id", "created_at") VALUES (1, '2016-01-01T00:00:00Z')
+//    _, err := db.From("items").Insert(Item{Id: 1, CreatedAt: created}).Exec()
+func (me *Database) NameMapper(mapper NameMapper) {
+	me.mapper = mapper
+}
+
+//used internally to get the NameMapper of the database
+func (me *Database) nameMapper() NameMapper {
+	return me.mapper
+}
+
+//Adds hooks that are called before and after each Exec, Query, QueryRow, Prepare, Commit and Rollback, transactions started
+//from the database inherit its hooks. See Hook.
+//    db.Use(goqu.HookFunc(func(ctx context.Context, event *goqu.QueryEvent) {
+//        if event.Err != nil {
+//            log.Printf("%s failed after %s: %s", event.Sql, event.Duration, event.Err)
+//        }
+//    }))
+//
+//hooks...: The hooks to add to the end of the chain
+func (me *Database) Use(hooks ...Hook) {
+	me.hooks = me.hooks.with(hooks...)
+}
+
+//used internally to log and run an operation through the hooks of the database
+func (me *Database) run(ctx context.Context, event *QueryEvent, fn func(ctx context.Context) error) error {
+	me.Trace(event.Op, event.Sql, event.Args...)
+	return me.hooks.run(ctx, event, func(ctx context.Context) error {
+		return newDatabaseError(me.queryAdapter(nil), event, fn(ctx))
+	})
+}
+
 //Logs a given operation with the specified sql and arguments
 func (me *Database) Trace(op, sql string, args ...interface{}) {
 	if me.logger != nil {
@@ -106,8 +330,88 @@ func (me *Database) Trace(op, sql string, args ...interface{}) {
 //
 //args...: for any placeholder parameters in the query
 func (me *Database) Exec(query string, args ...interface{}) (sql.Result, error) {
-	me.Trace("EXEC", query, args...)
-	return me.Db.Exec(query, args...)
+	return me.ExecContext(context.Background(), query, args...)
+}
+
+//Same as Exec but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//query: The SQL to execute
+//
+//args...: for any placeholder parameters in the query
+func (me *Database) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	event := newQueryEvent("EXEC", query, args, false)
+	err := me.run(ctx, event, func(ctx context.Context) (err error) {
+		result, err = me.Db.ExecContext(ctx, query, args...)
+		event.RowsAffected = rowsAffected(result)
+		return err
+	})
+	return result, err
+}
+
+//used internally to execute a prepared statement, if the statement cache is enabled the statement is only prepared once
+func (me *Database) preparedExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if me.stmtCache == nil {
+		return me.ExecContext(ctx, query, args...)
+	}
+	stmt, release, err := me.stmtCache.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	var result sql.Result
+	event := newQueryEvent("EXEC", query, args, false)
+	err = me.run(ctx, event, func(ctx context.Context) (err error) {
+		result, err = stmt.ExecContext(ctx, args...)
+		event.RowsAffected = rowsAffected(result)
+		return err
+	})
+	return result, err
+}
+
+//used internally to run the query of a CrudExec whose rows are read using a Scanner, the hooks are called after the query
+//once the Scanner is closed so the event includes the rows returned. Reads are routed to a replica if the CrudExec allows it
+//and if the CrudExec is prepared and the statement cache is enabled the statement is only prepared once.
+func (me *Database) scannerContext(ctx context.Context, exec CrudExec) (*Scanner, error) {
+	query, args := exec.Sql, exec.Args
+	db, cache := me.Db, me.stmtCache
+	if exec.replica && len(me.replicas) > 0 {
+		i := me.balancer.Next(len(me.replicas))
+		db = me.replicas[i]
+		if cache != nil {
+			cache = me.replicaStmtCaches[i]
+		}
+	}
+	queryFn := func(ctx context.Context) (*sql.Rows, error) {
+		return db.QueryContext(ctx, query, args...)
+	}
+	if exec.prepared && cache != nil {
+		stmt, release, err := cache.acquire(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		queryFn = func(ctx context.Context) (*sql.Rows, error) {
+			return stmt.QueryContext(ctx, args...)
+		}
+	}
+	event := newQueryEvent("QUERY", query, args, false)
+	me.Trace(event.Op, query, args...)
+	adapter := me.queryAdapter(nil)
+	scanner, err := me.hooks.scan(ctx, event, func(ctx context.Context) (*sql.Rows, error) {
+		rows, err := queryFn(ctx)
+		return rows, newDatabaseError(adapter, event, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	scanner.mode, scanner.mapper = exec.scanModeOr(me.scanMode), me.mapper
+	scanner.classify = func(err error) error {
+		return newDatabaseError(adapter, event, err)
+	}
+	return scanner, nil
 }
 
 //Can be used to prepare a query.
@@ -136,8 +440,28 @@ func (me *Database) Exec(query string, args ...interface{}) (sql.Result, error)
 //
 //query: The SQL statement to prepare.
 func (me *Database) Prepare(query string) (*sql.Stmt, error) {
-	me.Trace("PREPARE", query)
-	return me.Db.Prepare(query)
+	return me.PrepareContext(context.Background(), query)
+}
+
+//Same as Prepare but the preparation is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context used when preparing the statement
+//
+//query: The SQL statement to prepare.
+func (me *Database) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return me.preparer(me.Db)(ctx, query)
+}
+
+//used internally to create a function that prepares statements on the db, used for the primary and replicas
+func (me *Database) preparer(db *sql.DB) func(ctx context.Context, query string) (*sql.Stmt, error) {
+	return func(ctx context.Context, query string) (*sql.Stmt, error) {
+		var stmt *sql.Stmt
+		err := me.run(ctx, newQueryEvent("PREPARE", query, nil, false), func(ctx context.Context) (err error) {
+			stmt, err = db.PrepareContext(ctx, query)
+			return err
+		})
+		return stmt, err
+	}
 }
 
 //Used to query for multiple rows.
@@ -163,8 +487,23 @@ func (me *Database) Prepare(query string) (*sql.Stmt, error) {
 //
 //args...: for any placeholder parameters in the query
 func (me *Database) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	me.Trace("QUERY", query, args...)
-	return me.Db.Query(query, args...)
+	return me.QueryContext(context.Background(), query, args...)
+}
+
+//Same as Query but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//query: The SQL to execute
+//
+//args...: for any placeholder parameters in the query
+func (me *Database) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := me.run(ctx, newQueryEvent("QUERY", query, args, false), func(ctx context.Context) (err error) {
+		rows, err = me.Db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
 }
 
 //Used to query for a single row.
@@ -184,8 +523,23 @@ func (me *Database) Query(query string, args ...interface{}) (*sql.Rows, error)
 //
 //args...: for any placeholder parameters in the query
 func (me *Database) QueryRow(query string, args ...interface{}) *sql.Row {
-	me.Trace("QUERY ROW", query, args...)
-	return me.Db.QueryRow(query, args...)
+	return me.QueryRowContext(context.Background(), query, args...)
+}
+
+//Same as QueryRow but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//query: The SQL to execute
+//
+//args...: for any placeholder parameters in the query
+func (me *Database) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	var row *sql.Row
+	me.run(ctx, newQueryEvent("QUERY ROW", query, args, false), func(ctx context.Context) error {
+		row = me.Db.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
 }
 
 //Queries the database using the supplied query, and args and uses CrudExec.ScanStructs to scan the results into a slice of structs
@@ -196,8 +550,21 @@ func (me *Database) QueryRow(query string, args ...interface{}) *sql.Row {
 //
 //args...: for any placeholder parameters in the query
 func (me *Database) ScanStructs(i interface{}, query string, args ...interface{}) error {
+	return me.ScanStructsContext(context.Background(), i, query, args...)
+}
+
+//Same as ScanStructs but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: A pointer to a slice of structs
+//
+//query: The SQL to execute
+//
+//args...: for any placeholder parameters in the query
+func (me *Database) ScanStructsContext(ctx context.Context, i interface{}, query string, args ...interface{}) error {
 	exec := newCrudExec(me, nil, query, args...)
-	return exec.ScanStructs(i)
+	return exec.ScanStructsContext(ctx, i)
 }
 
 //Queries the database using the supplied query, and args and uses CrudExec.ScanStruct to scan the results into a struct
@@ -208,8 +575,21 @@ func (me *Database) ScanStructs(i interface{}, query string, args ...interface{}
 //
 //args...: for any placeholder parameters in the query
 func (me *Database) ScanStruct(i interface{}, query string, args ...interface{}) (bool, error) {
+	return me.ScanStructContext(context.Background(), i, query, args...)
+}
+
+//Same as ScanStruct but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: A pointer to a struct
+//
+//query: The SQL to execute
+//
+//args...: for any placeholder parameters in the query
+func (me *Database) ScanStructContext(ctx context.Context, i interface{}, query string, args ...interface{}) (bool, error) {
 	exec := newCrudExec(me, nil, query, args...)
-	return exec.ScanStruct(i)
+	return exec.ScanStructContext(ctx, i)
 }
 
 //Queries the database using the supplied query, and args and uses CrudExec.ScanVals to scan the results into a slice of primitive values
@@ -220,8 +600,21 @@ func (me *Database) ScanStruct(i interface{}, query string, args ...interface{})
 //
 //args...: for any placeholder parameters in the query
 func (me *Database) ScanVals(i interface{}, query string, args ...interface{}) error {
+	return me.ScanValsContext(context.Background(), i, query, args...)
+}
+
+//Same as ScanVals but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: A pointer to a slice of primitive values
+//
+//query: The SQL to execute
+//
+//args...: for any placeholder parameters in the query
+func (me *Database) ScanValsContext(ctx context.Context, i interface{}, query string, args ...interface{}) error {
 	exec := newCrudExec(me, nil, query, args...)
-	return exec.ScanVals(i)
+	return exec.ScanValsContext(ctx, i)
 }
 
 //Queries the database using the supplied query, and args and uses CrudExec.ScanVal to scan the results into a primitive value
@@ -232,15 +625,86 @@ func (me *Database) ScanVals(i interface{}, query string, args ...interface{}) e
 //
 //args...: for any placeholder parameters in the query
 func (me *Database) ScanVal(i interface{}, query string, args ...interface{}) (bool, error) {
+	return me.ScanValContext(context.Background(), i, query, args...)
+}
+
+//Same as ScanVal but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: A pointer to a primitive value
+//
+//query: The SQL to execute
+//
+//args...: for any placeholder parameters in the query
+func (me *Database) ScanValContext(ctx context.Context, i interface{}, query string, args ...interface{}) (bool, error) {
+	exec := newCrudExec(me, nil, query, args...)
+	return exec.ScanValContext(ctx, i)
+}
+
+//Queries the database using the supplied query, and args and uses CrudExec.ScanMaps to scan the results into a slice of Records
+//
+//i: A pointer to a slice of Records
+//
+//query: The SQL to execute
+//
+//args...: for any placeholder parameters in the query
+func (me *Database) ScanMaps(i *[]Record, query string, args ...interface{}) error {
+	return me.ScanMapsContext(context.Background(), i, query, args...)
+}
+
+//Same as ScanMaps but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: A pointer to a slice of Records
+//
+//query: The SQL to execute
+//
+//args...: for any placeholder parameters in the query
+func (me *Database) ScanMapsContext(ctx context.Context, i *[]Record, query string, args ...interface{}) error {
 	exec := newCrudExec(me, nil, query, args...)
-	return exec.ScanVal(i)
+	return exec.ScanMapsContext(ctx, i)
+}
+
+//Queries the database using the supplied query, and args and uses CrudExec.ScanMap to scan the result into a Record
+//
+//i: A pointer to a Record
+//
+//query: The SQL to execute
+//
+//args...: for any placeholder parameters in the query
+func (me *Database) ScanMap(i *Record, query string, args ...interface{}) (bool, error) {
+	return me.ScanMapContext(context.Background(), i, query, args...)
+}
+
+//Same as ScanMap but the query is aborted if the context is canceled or its deadline is exceeded.
+//
+//ctx: The context to execute the query with
+//
+//i: A pointer to a Record
+//
+//query: The SQL to execute
+//
+//args...: for any placeholder parameters in the query
+func (me *Database) ScanMapContext(ctx context.Context, i *Record, query string, args ...interface{}) (bool, error) {
+	exec := newCrudExec(me, nil, query, args...)
+	return exec.ScanMapContext(ctx, i)
 }
 
 //A wrapper around a sql.Tx and works the same way as Database
 type TxDatabase struct {
-	logger  Logger
-	Dialect string
-	Tx      *sql.Tx
+	logger     Logger
+	hooks      hookChain
+	ctx        context.Context
+	scanMode   ScanMode
+	mapper     NameMapper
+	wrapDepth  int
+	savepoints int
+	stmtCache  *stmtCache
+	stmts      map[string]*sql.Stmt
+	Dialect    string
+	Tx         *sql.Tx
 }
 
 //used internally to create a new query adapter for a Dataset
@@ -259,6 +723,42 @@ func (me *TxDatabase) Logger(logger Logger) {
 	me.logger = logger
 }
 
+//Sets how the columns returned by a query are matched to the fields of a struct when scanning. See Database#ScanMode
+func (me *TxDatabase) ScanMode(mode ScanMode) {
+	me.scanMode = mode
+}
+
+//Sets the NameMapper used to map the names of struct fields to column names. See Database#NameMapper
+func (me *TxDatabase) NameMapper(mapper NameMapper) {
+	me.mapper = mapper
+}
+
+//used internally to get the NameMapper of the transaction
+func (me *TxDatabase) nameMapper() NameMapper {
+	return me.mapper
+}
+
+//Adds hooks to the transaction, the hooks are not added to the database the transaction was started from. See Database#Use
+func (me *TxDatabase) Use(hooks ...Hook) {
+	me.hooks = me.hooks.with(hooks...)
+}
+
+//used internally to log and run an operation through the hooks of the transaction
+func (me *TxDatabase) run(ctx context.Context, event *QueryEvent, fn func(ctx context.Context) error) error {
+	me.Trace(event.Op, event.Sql, event.Args...)
+	return me.hooks.run(ctx, event, func(ctx context.Context) error {
+		return newDatabaseError(me.queryAdapter(nil), event, fn(ctx))
+	})
+}
+
+//used internally to get the context the transaction was started with
+func (me *TxDatabase) context() context.Context {
+	if me.ctx == nil {
+		return context.Background()
+	}
+	return me.ctx
+}
+
 func (me *TxDatabase) Trace(op, sql string, args ...interface{}) {
 	if me.logger != nil {
 		if sql != "" {
@@ -275,62 +775,253 @@ func (me *TxDatabase) Trace(op, sql string, args ...interface{}) {
 
 //See Database#Exec
 func (me *TxDatabase) Exec(query string, args ...interface{}) (sql.Result, error) {
-	me.Trace("EXEC", query, args...)
-	return me.Tx.Exec(query, args...)
+	return me.ExecContext(context.Background(), query, args...)
+}
+
+//See Database#ExecContext
+func (me *TxDatabase) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	event := newQueryEvent("EXEC", query, args, true)
+	err := me.run(ctx, event, func(ctx context.Context) (err error) {
+		result, err = me.Tx.ExecContext(ctx, query, args...)
+		event.RowsAffected = rowsAffected(result)
+		return err
+	})
+	return result, err
 }
 
 //See Database#Prepare
 func (me *TxDatabase) Prepare(query string) (*sql.Stmt, error) {
-	me.Trace("PREPARE", query)
-	return me.Tx.Prepare(query)
+	return me.PrepareContext(context.Background(), query)
+}
+
+//See Database#PrepareContext
+func (me *TxDatabase) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	var stmt *sql.Stmt
+	err := me.run(ctx, newQueryEvent("PREPARE", query, nil, true), func(ctx context.Context) (err error) {
+		stmt, err = me.Tx.PrepareContext(ctx, query)
+		return err
+	})
+	return stmt, err
+}
+
+//used internally to execute a prepared statement, see Database#preparedExecContext
+func (me *TxDatabase) preparedExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if me.stmtCache == nil {
+		return me.ExecContext(ctx, query, args...)
+	}
+	stmt, err := me.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var result sql.Result
+	event := newQueryEvent("EXEC", query, args, true)
+	err = me.run(ctx, event, func(ctx context.Context) (err error) {
+		result, err = stmt.ExecContext(ctx, args...)
+		event.RowsAffected = rowsAffected(result)
+		return err
+	})
+	return result, err
+}
+
+//used internally to run the query of a CrudExec whose rows are read using a Scanner, all reads within a transaction are
+//executed using the primary. See Database#scannerContext
+func (me *TxDatabase) scannerContext(ctx context.Context, exec CrudExec) (*Scanner, error) {
+	query, args := exec.Sql, exec.Args
+	queryFn := func(ctx context.Context) (*sql.Rows, error) {
+		return me.Tx.QueryContext(ctx, query, args...)
+	}
+	if exec.prepared && me.stmtCache != nil {
+		stmt, err := me.stmt(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		queryFn = func(ctx context.Context) (*sql.Rows, error) {
+			return stmt.QueryContext(ctx, args...)
+		}
+	}
+	event := newQueryEvent("QUERY", query, args, true)
+	me.Trace(event.Op, query, args...)
+	adapter := me.queryAdapter(nil)
+	scanner, err := me.hooks.scan(ctx, event, func(ctx context.Context) (*sql.Rows, error) {
+		rows, err := queryFn(ctx)
+		return rows, newDatabaseError(adapter, event, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	scanner.mode, scanner.mapper = exec.scanModeOr(me.scanMode), me.mapper
+	scanner.classify = func(err error) error {
+		return newDatabaseError(adapter, event, err)
+	}
+	return scanner, nil
+}
+
+//used internally to bind a statement from the statement cache of the database to the transaction, the bound statements
+//are closed when the transaction is committed or rolled back. A query that is not cached is prepared on the transaction,
+//preparing it on the database would need a second connection while the transaction holds one (e.g. SetMaxOpenConns(1)).
+func (me *TxDatabase) stmt(ctx context.Context, query string) (*sql.Stmt, error) {
+	if stmt, ok := me.stmts[query]; ok {
+		return stmt, nil
+	}
+	var stmt *sql.Stmt
+	if cached, release, ok := me.stmtCache.get(query); ok {
+		defer release()
+		stmt = me.Tx.StmtContext(ctx, cached)
+	} else {
+		var err error
+		if stmt, err = me.Tx.PrepareContext(ctx, query); err != nil {
+			return nil, err
+		}
+	}
+	if me.stmts == nil {
+		me.stmts = make(map[string]*sql.Stmt)
+	}
+	me.stmts[query] = stmt
+	return stmt, nil
 }
 
 //See Database#Query
 func (me *TxDatabase) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	me.Trace("QUERY", query, args...)
-	return me.Tx.Query(query, args...)
+	return me.QueryContext(context.Background(), query, args...)
+}
+
+//See Database#QueryContext
+func (me *TxDatabase) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := me.run(ctx, newQueryEvent("QUERY", query, args, true), func(ctx context.Context) (err error) {
+		rows, err = me.Tx.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
 }
 
 //See Database#QueryRow
 func (me *TxDatabase) QueryRow(query string, args ...interface{}) *sql.Row {
-	me.Trace("QUERY ROW", query, args...)
-	return me.Tx.QueryRow(query, args...)
+	return me.QueryRowContext(context.Background(), query, args...)
+}
+
+//See Database#QueryRowContext
+func (me *TxDatabase) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	var row *sql.Row
+	me.run(ctx, newQueryEvent("QUERY ROW", query, args, true), func(ctx context.Context) error {
+		row = me.Tx.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
 }
 
 //See Database#ScanStructs
 func (me *TxDatabase) ScanStructs(i interface{}, query string, args ...interface{}) error {
+	return me.ScanStructsContext(context.Background(), i, query, args...)
+}
+
+//See Database#ScanStructsContext
+func (me *TxDatabase) ScanStructsContext(ctx context.Context, i interface{}, query string, args ...interface{}) error {
 	exec := newCrudExec(me, nil, query, args...)
-	return exec.ScanStructs(i)
+	return exec.ScanStructsContext(ctx, i)
 }
 
 //See Database#ScanStruct
 func (me *TxDatabase) ScanStruct(i interface{}, query string, args ...interface{}) (bool, error) {
+	return me.ScanStructContext(context.Background(), i, query, args...)
+}
+
+//See Database#ScanStructContext
+func (me *TxDatabase) ScanStructContext(ctx context.Context, i interface{}, query string, args ...interface{}) (bool, error) {
 	exec := newCrudExec(me, nil, query, args...)
-	return exec.ScanStruct(i)
+	return exec.ScanStructContext(ctx, i)
 }
 
 //See Database#ScanVals
 func (me *TxDatabase) ScanVals(i interface{}, query string, args ...interface{}) error {
+	return me.ScanValsContext(context.Background(), i, query, args...)
+}
+
+//See Database#ScanValsContext
+func (me *TxDatabase) ScanValsContext(ctx context.Context, i interface{}, query string, args ...interface{}) error {
 	exec := newCrudExec(me, nil, query, args...)
-	return exec.ScanVals(i)
+	return exec.ScanValsContext(ctx, i)
 }
 
 //See Database#ScanVal
 func (me *TxDatabase) ScanVal(i interface{}, query string, args ...interface{}) (bool, error) {
+	return me.ScanValContext(context.Background(), i, query, args...)
+}
+
+//See Database#ScanValContext
+func (me *TxDatabase) ScanValContext(ctx context.Context, i interface{}, query string, args ...interface{}) (bool, error) {
 	exec := newCrudExec(me, nil, query, args...)
-	return exec.ScanVal(i)
+	return exec.ScanValContext(ctx, i)
+}
+
+//See Database#ScanMaps
+func (me *TxDatabase) ScanMaps(i *[]Record, query string, args ...interface{}) error {
+	return me.ScanMapsContext(context.Background(), i, query, args...)
+}
+
+//See Database#ScanMapsContext
+func (me *TxDatabase) ScanMapsContext(ctx context.Context, i *[]Record, query string, args ...interface{}) error {
+	exec := newCrudExec(me, nil, query, args...)
+	return exec.ScanMapsContext(ctx, i)
+}
+
+//See Database#ScanMap
+func (me *TxDatabase) ScanMap(i *Record, query string, args ...interface{}) (bool, error) {
+	return me.ScanMapContext(context.Background(), i, query, args...)
+}
+
+//See Database#ScanMapContext
+func (me *TxDatabase) ScanMapContext(ctx context.Context, i *Record, query string, args ...interface{}) (bool, error) {
+	exec := newCrudExec(me, nil, query, args...)
+	return exec.ScanMapContext(ctx, i)
 }
 
 //COMMIT the transaction
 func (me *TxDatabase) Commit() error {
-	me.Trace("COMMIT", "")
-	return me.Tx.Commit()
+	me.stmts = nil
+	return me.run(me.context(), newQueryEvent("COMMIT", "", nil, true), func(ctx context.Context) error {
+		return me.Tx.Commit()
+	})
 }
 
 //ROLLBACK the transaction
 func (me *TxDatabase) Rollback() error {
-	me.Trace("ROLLBACK", "")
-	return me.Tx.Rollback()
+	me.stmts = nil
+	return me.run(me.context(), newQueryEvent("ROLLBACK", "", nil, true), func(ctx context.Context) error {
+		return me.Tx.Rollback()
+	})
+}
+
+//Creates a SAVEPOINT with the given name within the transaction
+//
+//name: The name of the savepoint
+func (me *TxDatabase) Savepoint(name string) error {
+	return me.execSavepointSql(me.queryAdapter(nil).SavepointSql, name)
+}
+
+//Rolls back all statements executed after the SAVEPOINT with the given name was created, the transaction itself is not rolled back.
+//
+//name: The name of the savepoint
+func (me *TxDatabase) RollbackTo(name string) error {
+	return me.execSavepointSql(me.queryAdapter(nil).RollbackToSavepointSql, name)
+}
+
+//Releases the SAVEPOINT with the given name, the statements executed after the savepoint was created remain part of the transaction.
+//
+//name: The name of the savepoint
+func (me *TxDatabase) Release(name string) error {
+	return me.execSavepointSql(me.queryAdapter(nil).ReleaseSavepointSql, name)
+}
+
+//used internally to generate and execute savepoint sql
+func (me *TxDatabase) execSavepointSql(gen func(buf *SqlBuilder, name string) error, name string) error {
+	buf := NewSqlBuilder(false)
+	if err := gen(buf, name); err != nil {
+		return err
+	}
+	_, err := me.Exec(buf.String())
+	return err
 }
 
 //A helper method that will automatically COMMIT or ROLLBACK once the  supplied function is done executing
@@ -348,8 +1039,14 @@ func (me *TxDatabase) Rollback() error {
 //      }); err != nil{
 //           panic(err.Error()) //you could gracefully handle the error also
 //      }
+//
+//When Wrap is called within the function of another Wrap a SAVEPOINT is used instead, so an error only rolls back the
+//statements executed by the inner function and the outer transaction is not committed.
 func (me *TxDatabase) Wrap(fn func() error) error {
-	if err := fn(); err != nil {
+	if me.wrapDepth > 0 {
+		return me.wrapSavepoint(fn)
+	}
+	if err := me.callWrapped(fn); err != nil {
 		if rollbackErr := me.Rollback(); rollbackErr != nil {
 			return rollbackErr
 		}
@@ -357,3 +1054,28 @@ func (me *TxDatabase) Wrap(fn func() error) error {
 	}
 	return me.Commit()
 }
+
+//used internally to track how deeply calls to Wrap are nested while the function executes
+func (me *TxDatabase) callWrapped(fn func() error) error {
+	me.wrapDepth++
+	defer func() {
+		me.wrapDepth--
+	}()
+	return fn()
+}
+
+//used internally to run a nested Wrap function within a SAVEPOINT
+func (me *TxDatabase) wrapSavepoint(fn func() error) error {
+	me.savepoints++
+	name := fmt.Sprintf("goqu_savepoint_%d", me.savepoints)
+	if err := me.Savepoint(name); err != nil {
+		return err
+	}
+	if err := me.callWrapped(fn); err != nil {
+		if rollbackErr := me.RollbackTo(name); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+	return me.Release(name)
+}
@@ -1,8 +1,11 @@
 package goqu
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -26,6 +29,29 @@ func (me *dbTestMockLogger) Reset(format string, v ...interface{}) {
 	me.Messages = me.Messages[0:0]
 }
 
+var errTestRetryable = fmt.Errorf("serialization failure")
+
+type retryTestAdapter struct {
+	*DefaultAdapter
+}
+
+func (me *retryTestAdapter) IsRetryableTxError(err error) bool {
+	return err == errTestRetryable
+}
+
+var errTestUnique = fmt.Errorf("duplicate key value")
+
+type classifyTestAdapter struct {
+	*DefaultAdapter
+}
+
+func (me *classifyTestAdapter) ClassifyError(err error) error {
+	if err == errTestUnique {
+		return ErrUniqueViolation
+	}
+	return me.DefaultAdapter.ClassifyError(err)
+}
+
 type databaseTest struct {
 	suite.Suite
 }
@@ -151,6 +177,29 @@ func (me *databaseTest) TestScanVal() {
 	assert.EqualError(t, err, "goqu: Type must be a pointer when calling ScanVal")
 }
 
+func (me *databaseTest) TestScanMaps() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Test1").AddRow(int64(2), "Test2"))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" WHERE "id" = \?`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(2), "Test2"))
+
+	db := New("mock", mDb)
+	var records []Record
+	assert.NoError(t, db.ScanMaps(&records, `SELECT * FROM "items"`))
+	assert.Equal(t, records, []Record{{"id": int64(1), "name": "Test1"}, {"id": int64(2), "name": "Test2"}})
+
+	var record Record
+	found, err := db.ScanMap(&record, `SELECT * FROM "items" WHERE "id" = ?`, 2)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, record, Record{"id": int64(2), "name": "Test2"})
+}
+
 func (me *databaseTest) TestExec() {
 	t := me.T()
 	mDb, err := sqlmock.New()
@@ -223,6 +272,130 @@ func (me *databaseTest) TestPrepare() {
 	assert.NotNil(t, stmt)
 }
 
+func (me *databaseTest) TestStatementCache() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	byId := sqlmock.ExpectPrepare(`SELECT "address", "name" FROM "items" WHERE \("id" = \?\) LIMIT \?`)
+	byId.ExpectQuery().
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1"))
+	byId.ExpectQuery().
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("211 Test Addr,Test2"))
+	sqlmock.ExpectPrepare(`UPDATE "items" SET "name"=\? WHERE \("id" = \?\)`).
+		WillBeClosed().
+		ExpectExec().
+		WithArgs("Test3", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlmock.ExpectPrepare(`SELECT "address", "name" FROM "items" WHERE \("id" = \?\) LIMIT \?`).
+		ExpectQuery().
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test3"))
+
+	db := New("mock", mDb)
+	db.StatementCache(1)
+	ds := db.From("items").Prepared(true).Select("address", "name")
+	var item testActionItem
+	found, err := ds.Where(I("id").Eq(1)).ScanStruct(&item)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, item.Name, "Test1")
+	found, err = ds.Where(I("id").Eq(2)).ScanStruct(&item)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, item.Name, "Test2")
+	assert.Equal(t, db.stmtCache.len(), 1)
+
+	//evicts the SELECT statement
+	_, err = db.From("items").Prepared(true).Where(I("id").Eq(1)).Update(Record{"name": "Test3"}).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, db.stmtCache.len(), 1)
+
+	//evicts the UPDATE statement and prepares the SELECT statement again
+	found, err = ds.Where(I("id").Eq(1)).ScanStruct(&item)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, item.Name, "Test3")
+
+	db.StatementCache(0)
+	assert.Nil(t, db.stmtCache)
+}
+
+func (me *databaseTest) TestNewWithReplicas() {
+	t := me.T()
+	primaryDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectExec(`INSERT INTO "items" \("name"\) VALUES \('Test3'\)`).
+		WithArgs().
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" WHERE \("name" = 'Test3'\) LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("311 Test Addr,Test3"))
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectQuery(`SELECT COUNT\(\*\) AS "count" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).FromCSVString("3"))
+	sqlmock.ExpectCommit()
+
+	replicaDb1, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1\n211 Test Addr,Test2"))
+	sqlmock.ExpectQuery(`SELECT "name" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).FromCSVString("Test1\nTest2"))
+
+	replicaDb2, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT COUNT\(\*\) AS "count" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).FromCSVString("2"))
+
+	db := NewWithReplicas("mock", primaryDb, replicaDb1, replicaDb2)
+	var items []testActionItem
+	assert.NoError(t, db.From("items").ScanStructs(&items))
+	assert.Len(t, items, 2)
+	count, err := db.From("items").Count()
+	assert.NoError(t, err)
+	assert.Equal(t, count, int64(2))
+	var names []string
+	assert.NoError(t, db.From("items").Pluck(&names, "name"))
+	assert.Equal(t, names, []string{"Test1", "Test2"})
+
+	_, err = db.From("items").Insert(Record{"name": "Test3"}).Exec()
+	assert.NoError(t, err)
+	var item testActionItem
+	found, err := db.From("items").UsePrimary().Where(Ex{"name": "Test3"}).ScanStruct(&item)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, item.Name, "Test3")
+	assert.NoError(t, db.WithTx(nil, func(tx *TxDatabase) error {
+		count, err := tx.From("items").Count()
+		assert.Equal(t, count, int64(3))
+		return err
+	}))
+}
+
+func (me *databaseTest) TestReplicaBalancer_Nil() {
+	t := me.T()
+	primaryDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	replicaDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT "name" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).FromCSVString("Test1\nTest2"))
+
+	db := NewWithReplicas("mock", primaryDb, replicaDb)
+	db.ReplicaBalancer(nil)
+	assert.NotNil(t, db.balancer)
+	var names []string
+	assert.NoError(t, db.From("items").Pluck(&names, "name"))
+	assert.Equal(t, names, []string{"Test1", "Test2"})
+}
+
 func (me *databaseTest) TestBegin() {
 	t := me.T()
 	mDb, err := sqlmock.New()
@@ -238,6 +411,224 @@ func (me *databaseTest) TestBegin() {
 	assert.EqualError(t, err, "goqu: transaction error")
 }
 
+func (me *databaseTest) TestContext() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectExec(`UPDATE "items" SET "name"='Test1'`).
+		WithArgs().
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1\n211 Test Addr,Test2"))
+
+	db := New("mock", mDb)
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err = db.ExecContext(ctx, `UPDATE "items" SET "name"='Test1'`)
+	assert.NoError(t, err)
+	var items []testActionItem
+	assert.NoError(t, db.ScanStructsContext(ctx, &items, `SELECT * FROM "items"`))
+	assert.Len(t, items, 2)
+
+	cancel()
+	_, err = db.ExecContext(ctx, `UPDATE "items" SET "name"='Test1'`)
+	assert.EqualError(t, err, "context canceled")
+	_, err = db.QueryContext(ctx, `SELECT * FROM "items"`)
+	assert.EqualError(t, err, "context canceled")
+	var address, name string
+	assert.EqualError(t, db.QueryRowContext(ctx, `SELECT * FROM "items"`).Scan(&address, &name), "context canceled")
+	_, err = db.PrepareContext(ctx, `SELECT * FROM "items"`)
+	assert.EqualError(t, err, "context canceled")
+	assert.EqualError(t, db.ScanStructsContext(ctx, &items, `SELECT * FROM "items"`), "context canceled")
+	var item testActionItem
+	found, err := db.ScanStructContext(ctx, &item, `SELECT * FROM "items" LIMIT 1`)
+	assert.EqualError(t, err, "context canceled")
+	assert.False(t, found)
+	var ids []uint32
+	assert.EqualError(t, db.ScanValsContext(ctx, &ids, `SELECT "id" FROM "items"`), "context canceled")
+	var id uint32
+	found, err = db.ScanValContext(ctx, &id, `SELECT "id" FROM "items" LIMIT 1`)
+	assert.EqualError(t, err, "context canceled")
+	assert.False(t, found)
+}
+
+func (me *databaseTest) TestBeginTx() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectBegin().WillReturnError(NewGoquError("transaction error"))
+	db := New("mock", mDb)
+	logger := new(dbTestMockLogger)
+	db.Logger(logger)
+	tx, err := db.BeginTx(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Dialect, "mock")
+	assert.Equal(t, tx.logger, logger)
+
+	_, err = db.BeginTx(context.Background(), nil)
+	assert.EqualError(t, err, "goqu: transaction error")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = db.BeginTx(ctx, nil)
+	assert.EqualError(t, err, "context canceled")
+}
+
+func (me *databaseTest) TestWithTx() {
+	t := me.T()
+	RegisterAdapter("mock-retry", func(ds *Dataset) Adapter {
+		return &retryTestAdapter{NewDefaultAdapter(ds).(*DefaultAdapter)}
+	})
+	defer removeAdapter("mock-retry")
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	//retried after a serialization failure
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectExec(`UPDATE "items" SET "name"='Test1'`).WillReturnError(errTestRetryable)
+	sqlmock.ExpectRollback()
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectExec(`UPDATE "items" SET "name"='Test1'`).WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlmock.ExpectCommit()
+	//retried after a failed commit
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectCommit().WillReturnError(errTestRetryable)
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectCommit()
+	//not retried
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectRollback()
+
+	db := New("mock-retry", mDb)
+	var attempts []int
+	db.TxRetryPolicy(TxRetryPolicy{MaxRetries: 3, Backoff: func(attempt int) time.Duration {
+		attempts = append(attempts, attempt)
+		return 0
+	}})
+	calls := 0
+	assert.NoError(t, db.WithTx(nil, func(tx *TxDatabase) error {
+		calls++
+		_, err := tx.Exec(`UPDATE "items" SET "name"='Test1'`)
+		return err
+	}))
+	assert.Equal(t, calls, 2)
+	assert.Equal(t, attempts, []int{1})
+
+	calls = 0
+	assert.NoError(t, db.WithTx(nil, func(tx *TxDatabase) error {
+		calls++
+		return nil
+	}))
+	assert.Equal(t, calls, 2)
+
+	calls = 0
+	assert.EqualError(t, db.WithTx(nil, func(tx *TxDatabase) error {
+		calls++
+		return NewGoquError("tx error")
+	}), "goqu: tx error")
+	assert.Equal(t, calls, 1)
+}
+
+func (me *databaseTest) TestWithTx_RetriesExhausted() {
+	t := me.T()
+	RegisterAdapter("mock-retry", func(ds *Dataset) Adapter {
+		return &retryTestAdapter{NewDefaultAdapter(ds).(*DefaultAdapter)}
+	})
+	defer removeAdapter("mock-retry")
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectRollback()
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectRollback()
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectRollback()
+
+	db := New("mock-retry", mDb)
+	db.TxRetryPolicy(TxRetryPolicy{MaxRetries: 1})
+	calls := 0
+	assert.Equal(t, db.WithTx(nil, func(tx *TxDatabase) error {
+		calls++
+		return errTestRetryable
+	}), errTestRetryable)
+	assert.Equal(t, calls, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	db.TxRetryPolicy(TxRetryPolicy{MaxRetries: 3, Backoff: func(attempt int) time.Duration {
+		cancel()
+		return time.Hour
+	}})
+	calls = 0
+	assert.EqualError(t, db.WithTxContext(ctx, nil, func(tx *TxDatabase) error {
+		calls++
+		return errTestRetryable
+	}), "context canceled")
+	assert.Equal(t, calls, 1)
+}
+
+func (me *databaseTest) TestDatabaseError() {
+	t := me.T()
+	RegisterAdapter("mock-classify", func(ds *Dataset) Adapter {
+		return &classifyTestAdapter{NewDefaultAdapter(ds).(*DefaultAdapter)}
+	})
+	defer removeAdapter("mock-classify")
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectExec(`INSERT INTO "items" \("name"\) VALUES \(\?\)`).
+		WithArgs("Test1").
+		WillReturnError(errTestUnique)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnError(NewGoquError("mock error"))
+	sqlmock.ExpectQuery(`SELECT "id" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).FromCSVString("a"))
+
+	db := New("mock-classify", mDb)
+	_, err = db.Exec(`INSERT INTO "items" ("name") VALUES (?)`, "Test1")
+	assert.EqualError(t, err, "duplicate key value")
+	assert.True(t, errors.Is(err, ErrUniqueViolation))
+	assert.True(t, errors.Is(err, errTestUnique))
+	assert.False(t, errors.Is(err, ErrForeignKeyViolation))
+	//a user error with the same message is not a category
+	assert.False(t, errors.Is(NewGoquError("unique violation"), ErrUniqueViolation))
+	assert.False(t, NewGoquError("unique violation") == ErrUniqueViolation)
+	var dbErr *DatabaseError
+	assert.True(t, errors.As(err, &dbErr))
+	assert.Equal(t, dbErr.Kind, ErrUniqueViolation)
+	assert.Equal(t, dbErr.Op, "EXEC")
+	assert.Equal(t, dbErr.Sql, `INSERT INTO "items" ("name") VALUES (?)`)
+	assert.Equal(t, dbErr.Args, []interface{}{"Test1"})
+
+	var items []testActionItem
+	err = db.ScanStructs(&items, `SELECT * FROM "items"`)
+	assert.EqualError(t, err, "goqu: mock error")
+	assert.False(t, errors.Is(err, ErrUniqueViolation))
+	assert.True(t, errors.As(err, &dbErr))
+	assert.Nil(t, dbErr.Kind)
+	assert.Equal(t, dbErr.Op, "QUERY")
+
+	//errors scanning the rows are wrapped the same way as the errors of the query
+	var id int64
+	_, err = db.From("items").Select("id").ScanVal(&id)
+	assert.Error(t, err)
+	dbErr = nil
+	assert.True(t, errors.As(err, &dbErr))
+	assert.Nil(t, dbErr.Kind)
+	assert.Equal(t, dbErr.Op, "QUERY")
+	assert.Equal(t, dbErr.Sql, `SELECT "id" FROM "items" LIMIT 1`)
+}
+
+func (me *databaseTest) TestExponentialBackoff() {
+	t := me.T()
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	assert.Equal(t, backoff(1), 10*time.Millisecond)
+	assert.Equal(t, backoff(2), 20*time.Millisecond)
+	assert.Equal(t, backoff(3), 40*time.Millisecond)
+	assert.Equal(t, backoff(4), 50*time.Millisecond)
+	assert.Equal(t, backoff(100), 50*time.Millisecond)
+}
+
 func TestDatabaseSuite(t *testing.T) {
 	suite.Run(t, new(databaseTest))
 }
@@ -451,6 +842,31 @@ func (me *txDatabaseTest) TestScanVal() {
 	assert.NoError(t, tx.Commit())
 }
 
+func (me *txDatabaseTest) TestScanMaps() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Test1").AddRow(int64(2), "Test2"))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" WHERE "id" = \?`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	sqlmock.ExpectCommit()
+	tx, err := New("mock", mDb).Begin()
+	assert.NoError(t, err)
+	var records []Record
+	assert.NoError(t, tx.ScanMaps(&records, `SELECT * FROM "items"`))
+	assert.Equal(t, records, []Record{{"id": int64(1), "name": "Test1"}, {"id": int64(2), "name": "Test2"}})
+
+	var record Record
+	found, err := tx.ScanMap(&record, `SELECT * FROM "items" WHERE "id" = ?`, 3)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.NoError(t, tx.Commit())
+}
+
 func (me *txDatabaseTest) TestExec() {
 	t := me.T()
 	mDb, err := sqlmock.New()
@@ -521,6 +937,35 @@ func (me *txDatabaseTest) TestQueryRow() {
 	assert.NoError(t, tx.Commit())
 }
 
+func (me *txDatabaseTest) TestContext() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectQuery(`SELECT "id" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).FromCSVString("1\n2"))
+	sqlmock.ExpectCommit()
+	tx, err := New("mock", mDb).Begin()
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	var ids []uint32
+	assert.NoError(t, tx.ScanValsContext(ctx, &ids, `SELECT "id" FROM "items"`))
+	assert.Equal(t, ids, []uint32{1, 2})
+
+	cancel()
+	_, err = tx.ExecContext(ctx, `UPDATE "items" SET "name"='Test1'`)
+	assert.EqualError(t, err, "context canceled")
+	_, err = tx.QueryContext(ctx, `SELECT * FROM "items"`)
+	assert.EqualError(t, err, "context canceled")
+	var items []testActionItem
+	assert.EqualError(t, tx.ScanStructsContext(ctx, &items, `SELECT * FROM "items"`), "context canceled")
+	var id uint32
+	_, err = tx.ScanValContext(ctx, &id, `SELECT "id" FROM "items" LIMIT 1`)
+	assert.EqualError(t, err, "context canceled")
+	assert.NoError(t, tx.Commit())
+}
+
 func (me *txDatabaseTest) TestWrap() {
 	t := me.T()
 	mDb, err := sqlmock.New()
@@ -541,6 +986,110 @@ func (me *txDatabaseTest) TestWrap() {
 	}), "goqu: tx error")
 }
 
+func (me *txDatabaseTest) TestSavepoint() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectExec(`SAVEPOINT "sp1"`).WithArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlmock.ExpectExec(`ROLLBACK TO SAVEPOINT "sp1"`).WithArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlmock.ExpectExec(`RELEASE SAVEPOINT "sp1"`).WithArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlmock.ExpectExec(`SAVEPOINT "sp2"`).WithArgs().WillReturnError(NewGoquError("savepoint error"))
+	sqlmock.ExpectCommit()
+	tx, err := New("mock", mDb).Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Savepoint("sp1"))
+	assert.NoError(t, tx.RollbackTo("sp1"))
+	assert.NoError(t, tx.Release("sp1"))
+	assert.EqualError(t, tx.Savepoint("sp2"), "goqu: savepoint error")
+	assert.NoError(t, tx.Commit())
+}
+
+func (me *txDatabaseTest) TestWrap_Nested() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectExec(`SAVEPOINT "goqu_savepoint_1"`).WithArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlmock.ExpectExec(`SAVEPOINT "goqu_savepoint_2"`).WithArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlmock.ExpectExec(`RELEASE SAVEPOINT "goqu_savepoint_2"`).WithArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlmock.ExpectExec(`ROLLBACK TO SAVEPOINT "goqu_savepoint_1"`).WithArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlmock.ExpectExec(`SAVEPOINT "goqu_savepoint_3"`).WithArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlmock.ExpectExec(`RELEASE SAVEPOINT "goqu_savepoint_3"`).WithArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlmock.ExpectCommit()
+	tx, err := New("mock", mDb).Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Wrap(func() error {
+		assert.EqualError(t, tx.Wrap(func() error {
+			assert.NoError(t, tx.Wrap(func() error {
+				return nil
+			}))
+			return NewGoquError("inner error")
+		}), "goqu: inner error")
+		return tx.Wrap(func() error {
+			return nil
+		})
+	}))
+}
+
+func (me *txDatabaseTest) TestStatementCache() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectBegin()
+	//not cached by the database so it is prepared on the transaction
+	stmt := sqlmock.ExpectPrepare(`UPDATE "items" SET "name"=\? WHERE \("id" = \?\)`)
+	stmt.ExpectExec().
+		WithArgs("Test1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	stmt.ExpectExec().
+		WithArgs("Test2", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlmock.ExpectCommit()
+
+	db := New("mock", mDb)
+	db.StatementCache(10)
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	_, err = tx.From("items").Prepared(true).Where(I("id").Eq(1)).Update(Record{"name": "Test1"}).Exec()
+	assert.NoError(t, err)
+	_, err = tx.From("items").Prepared(true).Where(I("id").Eq(2)).Update(Record{"name": "Test2"}).Exec()
+	assert.NoError(t, err)
+	assert.Len(t, tx.stmts, 1)
+	assert.Equal(t, db.stmtCache.len(), 0)
+	assert.NoError(t, tx.Commit())
+	assert.Nil(t, tx.stmts)
+}
+
+func (me *txDatabaseTest) TestStatementCache_Cached() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	//prepared by the statement cache of the database
+	stmt := sqlmock.ExpectPrepare(`DELETE FROM "items" WHERE \("id" = \?\)`)
+	stmt.ExpectExec().
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlmock.ExpectBegin()
+	//the cached statement was prepared on the connection of the transaction so it is not prepared again
+	stmt.ExpectExec().
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlmock.ExpectCommit()
+
+	db := New("mock", mDb)
+	db.StatementCache(10)
+	_, err = db.From("items").Prepared(true).Where(I("id").Eq(1)).Delete().Exec()
+	assert.NoError(t, err)
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	_, err = tx.From("items").Prepared(true).Where(I("id").Eq(2)).Delete().Exec()
+	assert.NoError(t, err)
+	assert.Len(t, tx.stmts, 1)
+	assert.Equal(t, db.stmtCache.len(), 1)
+	assert.NoError(t, tx.Commit())
+}
+
 func TestTxDatabaseSuite(t *testing.T) {
 	suite.Run(t, new(txDatabaseTest))
 }
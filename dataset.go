@@ -1,6 +1,7 @@
 package goqu
 
 import (
+	"context"
 	"database/sql/driver"
 	"fmt"
 	"reflect"
@@ -31,6 +32,9 @@ type (
 		Offset         uint
 		Returning      ColumnList
 		Compounds      []CompoundExpression
+		ConflictTarget Expression
+		ConflictAction ConflictAction
+		InsertVariant  InsertVariant
 	}
 	//A Dataset is used to build up an SQL statement, each method returns a copy of the current Dataset with options added to it.
 	//Once done building up your Dataset you can either call an action method on it to execute the statement or use one of the SQL generation methods.
@@ -70,11 +74,21 @@ type (
 	//use the scan methods if the database supports return values. For example
 	//    UPDATE "items" SET updated = NOW RETURNING "items".*
 	//Could be executed with ScanStructs.
+	//
+	//To abort an action when a request is canceled or times out use WithContext, each action executed by the returned Dataset will use the context.
 	Dataset struct {
 		adapter    Adapter
 		clauses    clauses
 		database   database
 		isPrepared bool
+		usePrimary bool
+		chunkOpts  *InsertChunkOptions
+		pageInTx   bool
+		noRowsErr  bool
+		scanMode   ScanMode
+		ctx        context.Context
+		//an error building the dataset (e.g. SelectNested with a non struct), returned when the SELECT sql is generated
+		err error
 	}
 )
 
@@ -137,6 +151,54 @@ func (me *Dataset) Prepared(prepared bool) *Dataset {
 	return ret
 }
 
+//Sets the context used when executing actions (e.g. ScanStructs, Count, Insert().Exec()) on the returned dataset.
+//If the context is canceled or its deadline is exceeded the executing statement is aborted.
+//
+//ctx: The context to execute statements with
+func (me *Dataset) WithContext(ctx context.Context) *Dataset {
+	ret := me.copy()
+	ret.ctx = ctx
+	return ret
+}
+
+//Forces the read actions (e.g. ScanStructs, Count) of the returned dataset to be executed using the primary of a Database
+//created with NewWithReplicas, e.g. when you need to read a row you just wrote. See NewWithReplicas
+func (me *Dataset) UsePrimary() *Dataset {
+	ret := me.copy()
+	ret.usePrimary = true
+	return ret
+}
+
+//Sets how the columns returned by the struct scanning actions (e.g. ScanStructs, ScanStruct) of the returned dataset are
+//matched to the fields of the struct, overriding the ScanMode of the database. See ScanMode
+//    //discard any columns added to the items table that the Item struct does not have
+//    err := db.From("items").ScanMode(goqu.SCAN_LENIENT).ScanStructs(&items)
+func (me *Dataset) ScanMode(mode ScanMode) *Dataset {
+	ret := me.copy()
+	ret.scanMode = mode
+	return ret
+}
+
+//Makes the single row actions (e.g. ScanStruct, ScanVal, ScanMap) of the returned dataset return ErrNoRows instead of false
+//when no row is found.
+//    var item Item
+//    if _, err := db.From("items").Where(goqu.I("id").Eq(10)).ErrOnNoRows().ScanStruct(&item); errors.Is(err, goqu.ErrNoRows) {
+//        //handle the missing item
+//    }
+func (me *Dataset) ErrOnNoRows() *Dataset {
+	ret := me.copy()
+	ret.noRowsErr = true
+	return ret
+}
+
+//Returns the context actions will be executed with. If no context has been set context.Background() is returned.
+func (me *Dataset) Context() context.Context {
+	if me.ctx == nil {
+		return context.Background()
+	}
+	return me.ctx
+}
+
 //Returns the current adapter on the dataset
 func (me *Dataset) Adapter() Adapter {
 	return me.adapter
@@ -161,6 +223,44 @@ func (me Dataset) copy() *Dataset {
 	return &me
 }
 
+//used internally to get the NameMapper of the database, nil if the dataset does not have a database
+func (me *Dataset) nameMapper() NameMapper {
+	if me.database == nil {
+		return nil
+	}
+	return me.database.nameMapper()
+}
+
+//used internally to get the column name of a struct field when inserting or updating a struct, an empty string is
+//returned if the field should not be inserted or updated. A field without a db tag is only used if the database has a
+//NameMapper, unexported, embedded and nested struct fields (unless tagged with goqu:"json") without a db tag are never
+//used.
+func (me *Dataset) fieldColumnName(field reflect.StructField) string {
+	if columnName := field.Tag.Get("db"); columnName != "" {
+		return columnName
+	}
+	mapper := me.nameMapper()
+	if mapper == nil || field.PkgPath != "" || field.Anonymous || (nestedStructType(field.Type) != nil && !isJsonField(field)) {
+		return ""
+	}
+	return mapper.ColumnName(field.Name)
+}
+
+//used internally to get the value of a struct field to insert or update, if the adapter supports arrays a slice field
+//that is not tagged with goqu:"json" is inserted as an array or NULL if it is nil
+func (me *Dataset) fieldValue(field reflect.StructField, val reflect.Value) interface{} {
+	if isJsonField(field) {
+		return jsonValue{value: val.Interface()}
+	}
+	if isArrayType(field.Type) && me.adapter.SupportsArrays() {
+		if val.IsNil() {
+			return nil
+		}
+		return Array(val.Interface())
+	}
+	return val.Interface()
+}
+
 //Returns true if the dataset has a FROM clause
 func (me *Dataset) hasSources() bool {
 	return me.clauses.From != nil && len(me.clauses.From.Columns()) > 0
@@ -182,6 +282,17 @@ func (me *Dataset) Literal(buf *SqlBuilder, val interface{}) error {
 	}
 	if v, ok := val.(Expression); ok {
 		return me.expressionSql(buf, v)
+	} else if v := reflect.ValueOf(val); v.Kind() == reflect.Ptr && v.IsNil() {
+		//a nil pointer (e.g. a *string or *time.Time field of a struct) is NULL
+		return me.adapter.LiteralNil(buf)
+	} else if v, ok := val.(jsonValue); ok {
+		b, err := v.encode()
+		if err != nil {
+			return err
+		} else if b == nil {
+			return me.adapter.LiteralNil(buf)
+		}
+		return me.adapter.LiteralJson(buf, b)
 	} else if v, ok := val.(int); ok {
 		return me.adapter.LiteralInt(buf, int64(v))
 	} else if v, ok := val.(int32); ok {
@@ -278,6 +389,15 @@ func (me *Dataset) expressionSql(buf *SqlBuilder, expression Expression) error {
 		return me.adapter.SqlFunctionExpressionSql(buf, e)
 	} else if e, ok := expression.(CastExpression); ok {
 		return me.adapter.CastExpressionSql(buf, e)
+	} else if e, ok := expression.(ArrayExpression); ok {
+		if a, ok := e.(*array); ok {
+			if err := a.validate(); err != nil {
+				return err
+			}
+		}
+		return me.adapter.ArrayExpressionSql(buf, e)
+	} else if e, ok := expression.(ExcludedExpression); ok {
+		return me.adapter.ExcludedExpressionSql(buf, e)
 	} else if e, ok := expression.(*Dataset); ok {
 		return me.adapter.DatasetSql(buf, *e)
 	} else if e, ok := expression.(CompoundExpression); ok {
@@ -1,11 +1,16 @@
 package goqu
 
+import (
+	"reflect"
+	"strings"
+)
+
 //Generates the SELECT sql for this dataset and uses Exec#ScanStructs to scan the results into a slice of structs
 //
 //i: A pointer to a slice of structs
 func (me *Dataset) ScanStructs(i interface{}) error {
 	sql, args, err := me.ToSql()
-	return newCrudExec(me.database, err, sql, args...).ScanStructs(i)
+	return me.newReadCrudExec(err, sql, args...).ScanStructs(i)
 }
 
 //Generates the SELECT sql for this dataset and uses Exec#ScanStruct to scan the result into a slice of structs
@@ -13,7 +18,7 @@ func (me *Dataset) ScanStructs(i interface{}) error {
 //i: A pointer to a structs
 func (me *Dataset) ScanStruct(i interface{}) (bool, error) {
 	sql, args, err := me.Limit(1).ToSql()
-	return newCrudExec(me.database, err, sql, args...).ScanStruct(i)
+	return me.newReadCrudExec(err, sql, args...).ScanStruct(i)
 }
 
 //Generates the SELECT sql for this dataset and uses Exec#ScanVals to scan the results into a slice of primitive values
@@ -21,7 +26,7 @@ func (me *Dataset) ScanStruct(i interface{}) (bool, error) {
 //i: A pointer to a slice of primitive values
 func (me *Dataset) ScanVals(i interface{}) error {
 	sql, args, err := me.ToSql()
-	return newCrudExec(me.database, err, sql, args...).ScanVals(i)
+	return me.newReadCrudExec(err, sql, args...).ScanVals(i)
 }
 
 //Generates the SELECT sql for this dataset and uses Exec#ScanVal to scan the result into a primitive value
@@ -29,7 +34,88 @@ func (me *Dataset) ScanVals(i interface{}) error {
 //i: A pointer to a primitive value
 func (me *Dataset) ScanVal(i interface{}) (bool, error) {
 	sql, args, err := me.Limit(1).ToSql()
-	return newCrudExec(me.database, err, sql, args...).ScanVal(i)
+	return me.newReadCrudExec(err, sql, args...).ScanVal(i)
+}
+
+//Generates the SELECT sql for this dataset and uses Exec#ScanMaps to scan the results into a slice of Records
+//
+//i: A pointer to a slice of Records
+func (me *Dataset) ScanMaps(i *[]Record) error {
+	sql, args, err := me.ToSql()
+	return me.newReadCrudExec(err, sql, args...).ScanMaps(i)
+}
+
+//Generates the SELECT sql for this dataset and uses Exec#ScanMap to scan the result into a Record
+//
+//i: A pointer to a Record
+func (me *Dataset) ScanMap(i *Record) (bool, error) {
+	sql, args, err := me.Limit(1).ToSql()
+	return me.newReadCrudExec(err, sql, args...).ScanMap(i)
+}
+
+//Generates the SELECT sql for this dataset and uses Exec#ScanStructsMap to scan the results into a map of structs keyed
+//by the key column. If the values of the map are slices the rows are grouped by key, otherwise an error is returned for a
+//duplicate key.
+//    var items map[int64]Item
+//    err := db.From("items").ScanStructsMap(&items, "id")
+//    var itemsByName map[string][]Item
+//    err = db.From("items").ScanStructsMap(&itemsByName, "name")
+//
+//i: A pointer to a map of structs, pointers to structs or slices of either
+//
+//keyCol: The column to key the map by, it must map to a field of the struct
+func (me *Dataset) ScanStructsMap(i interface{}, keyCol string) error {
+	sql, args, err := me.ToSql()
+	return me.newReadCrudExec(err, sql, args...).ScanStructsMap(i, keyCol)
+}
+
+//Generates the SELECT sql only selecting the key and value columns and uses Exec#ScanValsMap to scan the results into a
+//map of primitive values keyed by the key column. If the values of the map are slices the values are grouped by key,
+//otherwise an error is returned for a duplicate key.
+//    var names map[int64]string
+//    err := db.From("items").ScanValsMap(&names, "id", "name")
+//
+//i: A pointer to a map of primitive values or slices of primitive values
+//
+//keyCol: The column to key the map by
+//
+//valCol: The column to use as the value
+func (me *Dataset) ScanValsMap(i interface{}, keyCol, valCol string) error {
+	sql, args, err := me.Select(keyCol, valCol).ToSql()
+	return me.newReadCrudExec(err, sql, args...).ScanValsMap(i)
+}
+
+//Generates the SELECT sql for this dataset and uses Exec#Iter to return a Scanner that iterates over the rows one at a time.
+//Use this instead of ScanStructs when the result set is too large to hold in memory. The Scanner must be closed when done.
+func (me *Dataset) Iter() (*Scanner, error) {
+	sql, args, err := me.ToSql()
+	return me.newReadCrudExec(err, sql, args...).Iter()
+}
+
+//Generates the SELECT sql for this dataset and calls the function with a Record for each row, rows are read one at a time.
+//If the function returns an error iteration stops and the error is returned.
+//    err := db.From("items").Each(func(r goqu.Record) error{
+//        fmt.Println(r["name"])
+//        return nil
+//    })
+//
+//fn: The function to call for each row
+func (me *Dataset) Each(fn func(Record) error) error {
+	scanner, err := me.Iter()
+	if err != nil {
+		return err
+	}
+	defer scanner.Close()
+	for scanner.Next() {
+		record, err := scanner.ScanRecord()
+		if err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
 }
 
 //Generates the SELECT COUNT(*) sql for this dataset and uses Exec#ScanVal to scan the result into an int64.
@@ -39,6 +125,114 @@ func (me *Dataset) Count() (int64, error) {
 	return count, err
 }
 
+//used internally to count the rows of this dataset, unlike Count grouped, distinct and compound datasets are counted
+//using a sub select so the total number of rows is returned instead of the count of the first group
+func (me *Dataset) countRows() (int64, error) {
+	var count int64
+	_, err := me.countDataset().ScanVal(&count)
+	return count, err
+}
+
+//used internally to create the dataset that counts the rows of this dataset
+func (me *Dataset) countDataset() *Dataset {
+	return me.aggregateDataset().Select(COUNT(Star()).As("count"))
+}
+
+//used internally to create the dataset to select an aggregate of the rows of this dataset from, grouped, distinct and
+//compound datasets are selected from using a sub select so the aggregate covers every row instead of the first group
+func (me *Dataset) aggregateDataset() *Dataset {
+	c := me.clauses
+	if hasColumns(c.GroupBy) || hasColumns(c.SelectDistinct) || c.Having != nil || len(c.Compounds) > 0 {
+		return me.FromSelf().Prepared(me.isPrepared)
+	}
+	return me
+}
+
+//used internally to check if a clause has any columns
+func hasColumns(cols ColumnList) bool {
+	return cols != nil && len(cols.Columns()) > 0
+}
+
+//Generates a SELECT EXISTS(...) sql for this dataset and returns true if the dataset has any rows. If the adapter does not
+//support SELECT EXISTS (See Adapter#SupportsSelectExists) SELECT 1 with a LIMIT of 1 is used instead.
+//    exists, err := db.From("items").Where(goqu.I("name").Eq("Test")).Exists()
+//    //SELECT EXISTS (SELECT * FROM "items" WHERE ("name" = 'Test')) LIMIT 1
+func (me *Dataset) Exists() (bool, error) {
+	if me.adapter.SupportsSelectExists() {
+		var exists bool
+		ds := me.copy()
+		ds.noRowsErr = false
+		ds.clauses = clauses{Select: cols(L("EXISTS ?", me))}
+		_, err := ds.ScanVal(&exists)
+		return exists, err
+	}
+	ds := me.aggregateDataset().copy()
+	ds.noRowsErr = false
+	var one int64
+	return ds.Select(L("1")).ScanVal(&one)
+}
+
+//Generates the SELECT SUM(col) sql for this dataset and scans the result into the value. If the dataset has no rows (the sum
+//is NULL) false is returned and the value is not changed. Grouped, distinct and compound datasets are aggregated using a
+//sub select, so col must be one of their selected columns.
+//    var total float64
+//    found, err := db.From("items").Sum("price", &total)
+//    //SELECT SUM("total") AS "sum" FROM (SELECT "name", SUM("price") AS "total" FROM "items" GROUP BY "name") AS "t1" LIMIT 1
+//    found, err = db.From("items").Select("name", goqu.SUM("price").As("total")).GroupBy("name").Sum("total", &total)
+//
+//col: The column to sum
+//
+//i: A pointer to a primitive value
+func (me *Dataset) Sum(col interface{}, i interface{}) (bool, error) {
+	return me.aggregate(SUM(col), "Sum", i)
+}
+
+//Generates the SELECT MIN(col) sql for this dataset and scans the result into the value. If the dataset has no rows (the
+//minimum is NULL) false is returned and the value is not changed.
+//
+//col: The column to get the minimum of
+//
+//i: A pointer to a primitive value
+func (me *Dataset) Min(col interface{}, i interface{}) (bool, error) {
+	return me.aggregate(MIN(col), "Min", i)
+}
+
+//Generates the SELECT MAX(col) sql for this dataset and scans the result into the value. If the dataset has no rows (the
+//maximum is NULL) false is returned and the value is not changed.
+//
+//col: The column to get the maximum of
+//
+//i: A pointer to a primitive value
+func (me *Dataset) Max(col interface{}, i interface{}) (bool, error) {
+	return me.aggregate(MAX(col), "Max", i)
+}
+
+//Generates the SELECT AVG(col) sql for this dataset and scans the result into the value. If the dataset has no rows (the
+//average is NULL) false is returned and the value is not changed.
+//
+//col: The column to average
+//
+//i: A pointer to a primitive value
+func (me *Dataset) Avg(col interface{}, i interface{}) (bool, error) {
+	return me.aggregate(AVG(col), "Avg", i)
+}
+
+//used internally to select an aggregate function and scan the result into the value, the result is scanned into a
+//pointer to the type of the value first so a NULL result does not cause a scan error.
+func (me *Dataset) aggregate(fn SqlFunctionExpression, name string, i interface{}) (bool, error) {
+	val := reflect.ValueOf(i)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return false, NewGoquError("Type must be a pointer when calling %s", name)
+	}
+	result := reflect.New(val.Type())
+	found, err := me.aggregateDataset().Select(fn.As(strings.ToLower(name))).ScanVal(result.Interface())
+	if err != nil || !found || result.Elem().IsNil() {
+		return false, err
+	}
+	val.Elem().Set(result.Elem().Elem())
+	return true, nil
+}
+
 //Generates the SELECT sql only selecting the passed in column and uses Exec#ScanVals to scan the result into a slice of primitive values.
 //
 //i: A slice of primitive values
@@ -54,7 +248,7 @@ func (me *Dataset) Pluck(i interface{}, col string) error {
 //See Dataset#UpdateSql for arguments
 func (me *Dataset) Update(i interface{}) *CrudExec {
 	sql, args, err := me.ToUpdateSql(i)
-	return newCrudExec(me.database, err, sql, args...)
+	return me.newCrudExec(err, sql, args...)
 }
 
 //Generates the UPDATE sql, and returns an Exec struct with the sql set to the INSERT statement
@@ -62,13 +256,59 @@ func (me *Dataset) Update(i interface{}) *CrudExec {
 //
 //See Dataset#InsertSql for arguments
 func (me *Dataset) Insert(i ...interface{}) *CrudExec {
+	if me.chunkOpts != nil {
+		return me.chunkedInsert(i...)
+	}
 	sql, args, err := me.ToInsertSql(i...)
-	return newCrudExec(me.database, err, sql, args...)
+	return me.newCrudExec(err, sql, args...)
+}
+
+//used internally to create a CrudExec that executes an INSERT statement for each chunk of rows, the Sql and Args of the
+//returned CrudExec are those of the first statement. See Dataset#ChunkInserts
+func (me *Dataset) chunkedInsert(rows ...interface{}) *CrudExec {
+	chunks, err := me.chunkInsertRows(rows...)
+	if err != nil {
+		return me.newCrudExec(err, "")
+	}
+	if len(chunks) < 2 {
+		sql, args, err := me.ToInsertSql(rows...)
+		return me.newCrudExec(err, sql, args...)
+	}
+	var exec *CrudExec
+	for _, chunk := range chunks {
+		sql, args, err := me.ToInsertSql(chunk...)
+		if err != nil {
+			return me.newCrudExec(err, sql, args...)
+		}
+		if exec == nil {
+			exec = me.newCrudExec(nil, sql, args...)
+			exec.chunkInTx = me.chunkOpts.InTx
+		}
+		exec.chunks = append(exec.chunks, me.newCrudExec(nil, sql, args...))
+	}
+	return exec
 }
 
 //Generates the DELETE sql, and returns an Exec struct with the sql set to the DELETE statement
 //    db.From("test").Where(I("id").Gt(10)).Exec()
 func (me *Dataset) Delete() *CrudExec {
 	sql, args, err := me.ToDeleteSql()
-	return newCrudExec(me.database, err, sql, args...)
+	return me.newCrudExec(err, sql, args...)
+}
+
+//used internally to create a CrudExec that executes statements using the database and context of the dataset
+func (me *Dataset) newCrudExec(err error, sql string, args ...interface{}) *CrudExec {
+	exec := newCrudExec(me.database, err, sql, args...)
+	exec.ctx = me.ctx
+	exec.prepared = me.isPrepared
+	exec.noRowsErr = me.noRowsErr
+	exec.scanMode = me.scanMode
+	return exec
+}
+
+//used internally to create a CrudExec for a read action, the read is routed to a replica unless UsePrimary was called
+func (me *Dataset) newReadCrudExec(err error, sql string, args ...interface{}) *CrudExec {
+	exec := me.newCrudExec(err, sql, args...)
+	exec.replica = !me.usePrimary
+	return exec
 }
@@ -1,6 +1,9 @@
 package goqu
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
@@ -214,6 +217,139 @@ func (me *datasetTest) TestScanVal_WithPreparedStatement() {
 	assert.EqualError(t, err, "goqu: Type must be a pointer when calling ScanVal")
 }
 
+func (me *datasetTest) TestScanMode() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name", "created"}).FromCSVString("111 Test Addr,Test1,2016-01-01"))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name", "created"}).FromCSVString("111 Test Addr,Test1,2016-01-01"))
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name", "created"}).FromCSVString("111 Test Addr,Test1,2016-01-01"))
+	sqlmock.ExpectCommit()
+
+	db := New("mock", mDb)
+	db.ScanMode(SCAN_LENIENT)
+	var items []dsTestActionItem
+	assert.NoError(t, db.From("items").ScanStructs(&items))
+	assert.Equal(t, items, []dsTestActionItem{{Address: "111 Test Addr", Name: "Test1"}})
+
+	var item dsTestActionItem
+	_, err = db.From("items").ScanMode(SCAN_DEFAULT).ScanStruct(&item)
+	assert.EqualError(t, err, `goqu: Unable to find corresponding field to column "created" returned by query`)
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	found, err := tx.From("items").ScanStruct(&item)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, item, dsTestActionItem{Address: "111 Test Addr", Name: "Test1"})
+	assert.NoError(t, tx.Commit())
+}
+
+func (me *datasetTest) TestErrOnNoRows() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}))
+	sqlmock.ExpectQuery(`SELECT "id" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}))
+
+	db := New("mock", mDb)
+	var item dsTestActionItem
+	found, err := db.From("items").ErrOnNoRows().ScanStruct(&item)
+	assert.Equal(t, err, ErrNoRows)
+	assert.False(t, found)
+	var id int64
+	found, err = db.From("items").Select("id").ErrOnNoRows().ScanVal(&id)
+	assert.Equal(t, err, ErrNoRows)
+	assert.False(t, found)
+	var record Record
+	found, err = db.From("items").ErrOnNoRows().ScanMap(&record)
+	assert.Equal(t, err, ErrNoRows)
+	assert.False(t, found)
+	found, err = db.From("items").ScanStruct(&item)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func (me *datasetTest) TestScanMaps() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT "address", "name" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1\n211 Test Addr,Test2"))
+
+	db := New("mock", mDb)
+	var records []Record
+	assert.NoError(t, db.From("items").Select("address", "name").ScanMaps(&records))
+	assert.Equal(t, records, []Record{
+		{"address": []byte("111 Test Addr"), "name": []byte("Test1")},
+		{"address": []byte("211 Test Addr"), "name": []byte("Test2")},
+	})
+}
+
+func (me *datasetTest) TestScanMap() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" WHERE \("name" = \?\) LIMIT \?`).
+		WithArgs("Test1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1"))
+
+	db := New("mock", mDb)
+	var record Record
+	found, err := db.From("items").Prepared(true).Where(I("name").Eq("Test1")).ScanMap(&record)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, record, Record{"address": []byte("111 Test Addr"), "name": []byte("Test1")})
+}
+
+func (me *datasetTest) TestScanStructsMap() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" WHERE \("address" = '111 Test Addr'\)`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1\n111 Test Addr,Test2"))
+
+	db := New("mock", mDb)
+	var items map[string][]dsTestActionItem
+	assert.NoError(t, db.From("items").Where(I("address").Eq("111 Test Addr")).ScanStructsMap(&items, "address"))
+	assert.Equal(t, items, map[string][]dsTestActionItem{
+		"111 Test Addr": {{Address: "111 Test Addr", Name: "Test1"}, {Address: "111 Test Addr", Name: "Test2"}},
+	})
+}
+
+func (me *datasetTest) TestScanValsMap() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT "name", "address" FROM "items" WHERE \("id" > \?\)`).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "address"}).FromCSVString("Test1,111 Test Addr\nTest2,211 Test Addr"))
+
+	db := New("mock", mDb)
+	var addresses map[string]string
+	assert.NoError(t, db.From("items").Prepared(true).Where(I("id").Gt(10)).ScanValsMap(&addresses, "name", "address"))
+	assert.Equal(t, addresses, map[string]string{"Test1": "111 Test Addr", "Test2": "211 Test Addr"})
+}
+
 func (me *datasetTest) TestCount() {
 	t := me.T()
 	mDb, err := sqlmock.New()
@@ -228,6 +364,21 @@ func (me *datasetTest) TestCount() {
 	assert.Equal(t, count, 10)
 }
 
+func (me *datasetTest) TestCount_Grouped() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT COUNT\(\*\) AS "count" FROM "items" GROUP BY "name" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).FromCSVString("3"))
+
+	db := New("mock", mDb)
+	//grouped datasets return the count of the first group
+	count, err := db.From("items").Select("name").GroupBy("name").Count()
+	assert.NoError(t, err)
+	assert.Equal(t, count, int64(3))
+}
+
 func (me *datasetTest) TestCount_WithPreparedStatement() {
 	t := me.T()
 	mDb, err := sqlmock.New()
@@ -245,6 +396,165 @@ func (me *datasetTest) TestCount_WithPreparedStatement() {
 	assert.Equal(t, count, 10)
 }
 
+func (me *datasetTest) TestExists() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT EXISTS \(SELECT \* FROM "items" WHERE \("name" = 'Test'\)\) LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).FromCSVString("true"))
+	sqlmock.ExpectQuery(`SELECT EXISTS \(SELECT \* FROM "items" WHERE \("name" = \?\)\) LIMIT \?`).
+		WithArgs("Test", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).FromCSVString("false"))
+
+	db := New("mock", mDb)
+	exists, err := db.From("items").Where(I("name").Eq("Test")).Exists()
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = db.From("items").Prepared(true).Where(I("name").Eq("Test")).Exists()
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func (me *datasetTest) TestExists_WithoutSelectExists() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT 1 FROM "items" WHERE \("name" = 'Test'\) LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).FromCSVString("1"))
+	sqlmock.ExpectQuery(`SELECT 1 FROM \(SELECT \* FROM "items" UNION \(SELECT \* FROM "other"\)\) AS "t1" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+
+	db := New("no-exists", mDb)
+	exists, err := db.From("items").Where(I("name").Eq("Test")).Exists()
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = db.From("items").Union(db.From("other")).ErrOnNoRows().Exists()
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func (me *datasetTest) TestExists_WithoutSelectExistsGrouped() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT 1 FROM \(SELECT "name" FROM "items" GROUP BY "name" HAVING \(COUNT\(\*\) > 1\)\) AS "t1" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).FromCSVString("1"))
+	sqlmock.ExpectQuery(`SELECT 1 FROM \(SELECT DISTINCT "name" FROM "items"\) AS "t1" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+
+	db := New("no-exists", mDb)
+	exists, err := db.From("items").Select("name").GroupBy("name").Having(COUNT(Star()).Gt(1)).Exists()
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = db.From("items").SelectDistinct("name").Exists()
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func (me *datasetTest) TestAggregates() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT SUM\("price"\) AS "sum" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).FromCSVString("10.5"))
+	sqlmock.ExpectQuery(`SELECT MIN\("id"\) AS "min" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"min"}).FromCSVString("1"))
+	sqlmock.ExpectQuery(`SELECT MAX\("name"\) AS "max" FROM "items" WHERE \("id" > 1\) LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).FromCSVString("Test2"))
+	sqlmock.ExpectQuery(`SELECT AVG\("price"\) AS "avg" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"avg"}).FromCSVString("2.5"))
+
+	db := New("mock", mDb)
+	var sum float64
+	found, err := db.From("items").Sum("price", &sum)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, sum, 10.5)
+	var min int64
+	found, err = db.From("items").Min("id", &min)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, min, int64(1))
+	var max string
+	found, err = db.From("items").Where(I("id").Gt(1)).Max("name", &max)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, max, "Test2")
+	var avg float64
+	found, err = db.From("items").Avg("price", &avg)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, avg, 2.5)
+
+	_, err = db.From("items").Sum("price", sum)
+	assert.EqualError(t, err, "goqu: Type must be a pointer when calling Sum")
+}
+
+func (me *datasetTest) TestAggregates_Grouped() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT SUM\("total"\) AS "sum" FROM \(SELECT "name", SUM\("price"\) AS "total" FROM "items" GROUP BY "name"\) AS "t1" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).FromCSVString("10.5"))
+	sqlmock.ExpectQuery(`SELECT MAX\("name"\) AS "max" FROM \(SELECT DISTINCT "name" FROM "items"\) AS "t1" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).FromCSVString("Test2"))
+	sqlmock.ExpectQuery(`SELECT MIN\("id"\) AS "min" FROM \(SELECT \* FROM "items" UNION \(SELECT \* FROM "other"\)\) AS "t1" LIMIT \?`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"min"}).FromCSVString("1"))
+
+	db := New("mock", mDb)
+	var sum float64
+	found, err := db.From("items").Select("name", SUM("price").As("total")).GroupBy("name").Sum("total", &sum)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, sum, 10.5)
+	var max string
+	found, err = db.From("items").SelectDistinct("name").Max("name", &max)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, max, "Test2")
+	var min int64
+	found, err = db.From("items").Union(db.From("other")).Prepared(true).Min("id", &min)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, min, int64(1))
+}
+
+func (me *datasetTest) TestAggregates_Null() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT SUM\("price"\) AS "sum" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(nil))
+	sqlmock.ExpectQuery(`SELECT MAX\("name"\) AS "max" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"max"}))
+
+	db := New("mock", mDb)
+	sum := 1.5
+	found, err := db.From("items").Sum("price", &sum)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, sum, 1.5)
+	var max string
+	found, err = db.From("items").Max("name", &max)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, max, "")
+}
+
 func (me *datasetTest) TestPluck() {
 	t := me.T()
 	mDb, err := sqlmock.New()
@@ -338,6 +648,69 @@ func (me *datasetTest) TestInsert_WithPreparedStatment() {
 	assert.NoError(t, err)
 }
 
+func (me *datasetTest) TestInsert_Chunked() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectExec(`INSERT INTO "items" \("address", "name"\) VALUES \(\?, \?\), \(\?, \?\)`).
+		WithArgs("111 Test Addr", "Test1", "211 Test Addr", "Test2").
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	sqlmock.ExpectExec(`INSERT INTO "items" \("address", "name"\) VALUES \(\?, \?\)`).
+		WithArgs("311 Test Addr", "Test3").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	sqlmock.ExpectQuery(`INSERT INTO "items" \("address", "name"\) VALUES \(\?, \?\), \(\?, \?\) RETURNING "id"`).
+		WithArgs("111 Test Addr", "Test1", "211 Test Addr", "Test2").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).FromCSVString("1\n2"))
+	sqlmock.ExpectQuery(`INSERT INTO "items" \("address", "name"\) VALUES \(\?, \?\) RETURNING "id"`).
+		WithArgs("311 Test Addr", "Test3").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).FromCSVString("3"))
+
+	db := New("mock", mDb)
+	rows := []Record{
+		{"address": "111 Test Addr", "name": "Test1"},
+		{"address": "211 Test Addr", "name": "Test2"},
+		{"address": "311 Test Addr", "name": "Test3"},
+	}
+	ds := db.From("items").Prepared(true).ChunkInserts(InsertChunkOptions{Size: 2})
+	result, err := ds.Insert(rows).Exec()
+	assert.NoError(t, err)
+	rowsAffected, err := result.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, rowsAffected, int64(3))
+	lastInsertId, err := result.LastInsertId()
+	assert.NoError(t, err)
+	assert.Equal(t, lastInsertId, int64(3))
+
+	var ids []int64
+	assert.NoError(t, ds.Returning("id").Insert(rows).ScanVals(&ids))
+	assert.Equal(t, ids, []int64{1, 2, 3})
+
+	var id int64
+	_, err = ds.Returning("id").Insert(rows).ScanVal(&id)
+	assert.EqualError(t, err, "goqu: Cannot call ScanVal on a chunked insert, use ScanVals")
+}
+
+func (me *datasetTest) TestInsert_ChunkedInTx() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectExec(`INSERT INTO "items" \("address", "name"\) VALUES \('111 Test Addr', 'Test1'\)`).
+		WithArgs().
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock.ExpectExec(`INSERT INTO "items" \("address", "name"\) VALUES \('211 Test Addr', 'Test2'\)`).
+		WithArgs().
+		WillReturnError(NewGoquError("insert error"))
+	sqlmock.ExpectRollback()
+
+	db := New("mock", mDb)
+	_, err = db.From("items").ChunkInserts(InsertChunkOptions{Size: 1, InTx: true}).Insert(
+		Record{"address": "111 Test Addr", "name": "Test1"},
+		Record{"address": "211 Test Addr", "name": "Test2"},
+	).Exec()
+	assert.EqualError(t, err, "goqu: insert error")
+}
+
 func (me *datasetTest) TestDelete() {
 	t := me.T()
 	mDb, err := sqlmock.New()
@@ -363,3 +736,97 @@ func (me *datasetTest) TestDelete_WithPreparedStatment() {
 	_, err = db.From("items").Prepared(true).Where(Ex{"id": Op{"gt": 10}}).Delete().Exec()
 	assert.NoError(t, err)
 }
+
+func (me *datasetTest) TestIter() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" WHERE \("address" = \?\)`).
+		WithArgs("111 Test Addr").
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1\n111 Test Addr,Test2"))
+
+	db := New("mock", mDb)
+	scanner, err := db.From("items").Prepared(true).Where(Ex{"address": "111 Test Addr"}).Iter()
+	assert.NoError(t, err)
+	defer scanner.Close()
+	var names []string
+	for scanner.Next() {
+		var item dsTestActionItem
+		assert.NoError(t, scanner.ScanStruct(&item))
+		names = append(names, item.Name)
+	}
+	assert.NoError(t, scanner.Err())
+	assert.Equal(t, names, []string{"Test1", "Test2"})
+}
+
+func (me *datasetTest) TestEach() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1\n211 Test Addr,Test2"))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1\n211 Test Addr,Test2"))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnError(fmt.Errorf("query error"))
+
+	db := New("mock", mDb)
+	var records []Record
+	assert.NoError(t, db.From("items").Each(func(r Record) error {
+		records = append(records, r)
+		return nil
+	}))
+	assert.Equal(t, records, []Record{
+		{"address": []byte("111 Test Addr"), "name": []byte("Test1")},
+		{"address": []byte("211 Test Addr"), "name": []byte("Test2")},
+	})
+
+	calls := 0
+	assert.EqualError(t, db.From("items").Each(func(r Record) error {
+		calls++
+		return fmt.Errorf("each error")
+	}), "each error")
+	assert.Equal(t, calls, 1)
+
+	assert.EqualError(t, db.From("items").Each(func(r Record) error {
+		return nil
+	}), "query error")
+}
+
+func (me *datasetTest) TestWithContext() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT COUNT\(\*\) AS "count" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).FromCSVString("10"))
+
+	db := New("mock", mDb)
+	ds := db.From("items")
+	assert.Equal(t, ds.Context(), context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ds = ds.WithContext(ctx)
+	assert.Equal(t, ds.Context(), ctx)
+	assert.Equal(t, ds.Where(I("id").Gt(10)).Context(), ctx)
+	count, err := ds.Count()
+	assert.NoError(t, err)
+	assert.Equal(t, count, int64(10))
+
+	cancel()
+	_, err = ds.Count()
+	assert.EqualError(t, err, "context canceled")
+	var ids []uint32
+	assert.EqualError(t, ds.Pluck(&ids, "id"), "context canceled")
+	var items []dsTestActionItem
+	assert.EqualError(t, ds.ScanStructs(&items), "context canceled")
+	_, err = ds.Insert(Record{"address": "111 Test Addr", "name": "Test1"}).Exec()
+	assert.EqualError(t, err, "context canceled")
+	_, err = ds.Update(Record{"name": "Test1"}).Exec()
+	assert.EqualError(t, err, "context canceled")
+	_, err = ds.Delete().Exec()
+	assert.EqualError(t, err, "context canceled")
+}
@@ -3,14 +3,145 @@ package goqu
 import (
 	"reflect"
 	"sort"
+	"strings"
 )
 
+type (
+	//Options to use when splitting the rows of an insert into multiple INSERT statements. See Dataset#ChunkInserts
+	InsertChunkOptions struct {
+		//The maximum number of rows to insert per statement. If 0 the number of rows is derived from the placeholder limit
+		//of the adapter (See Adapter#PlaceholderLimit) when the dataset is prepared, otherwise the rows are not split.
+		Size int
+		//Set to true to execute all statements within a single transaction. If the dataset was created from a TxDatabase
+		//the statements are always executed within its transaction.
+		InTx bool
+	}
+	//The conflict resolution of an INSERT statement. See Dataset#InsertIgnore, Dataset#Replace and Dataset#InsertAs
+	InsertVariant int
+)
+
+const (
+	//INSERT INTO
+	INSERT_DEFAULT InsertVariant = iota
+	//Skips rows that violate a constraint. INSERT OR IGNORE INTO (sqlite3), INSERT IGNORE INTO (mysql)
+	INSERT_IGNORE
+	//Deletes the existing rows that conflict before inserting. INSERT OR REPLACE INTO (sqlite3), REPLACE INTO (mysql)
+	INSERT_REPLACE
+	//Aborts the statement, reverting its changes, on a constraint violation. INSERT OR ABORT INTO (sqlite3)
+	INSERT_ABORT
+	//Aborts the statement, keeping the changes already made, on a constraint violation. INSERT OR FAIL INTO (sqlite3)
+	INSERT_FAIL
+	//Rolls back the transaction on a constraint violation. INSERT OR ROLLBACK INTO (sqlite3)
+	INSERT_ROLLBACK
+)
+
+func (me InsertVariant) String() string {
+	switch me {
+	case INSERT_IGNORE:
+		return "INSERT IGNORE"
+	case INSERT_REPLACE:
+		return "INSERT REPLACE"
+	case INSERT_ABORT:
+		return "INSERT ABORT"
+	case INSERT_FAIL:
+		return "INSERT FAIL"
+	case INSERT_ROLLBACK:
+		return "INSERT ROLLBACK"
+	}
+	return "INSERT"
+}
+
+//Splits the rows passed to Insert into multiple INSERT statements that are executed in sequence, use this when inserting
+//more rows than the placeholder limit of the database allows in a single prepared statement. See InsertChunkOptions
+//    result, err := db.From("items").Prepared(true).ChunkInserts(goqu.InsertChunkOptions{InTx: true}).Insert(items).Exec()
+//    if err != nil{
+//        panic(err.Error())
+//    }
+//    //the number of rows inserted by all statements
+//    inserted, err := result.RowsAffected()
+//
+//opts: The options to use when splitting the rows
+func (me *Dataset) ChunkInserts(opts InsertChunkOptions) *Dataset {
+	ret := me.copy()
+	ret.chunkOpts = &opts
+	return ret
+}
+
+//Skips the rows of an INSERT that violate a constraint (e.g. a duplicate key). See INSERT_IGNORE
+//    //INSERT OR IGNORE INTO `items` (`id`, `name`) VALUES (1, 'Test') //sqlite3
+//    //INSERT IGNORE INTO `items` (`id`, `name`) VALUES (1, 'Test') //mysql
+//    db.From("items").InsertIgnore().Insert(goqu.Record{"id": 1, "name": "Test"})
+func (me *Dataset) InsertIgnore() *Dataset {
+	return me.InsertAs(INSERT_IGNORE)
+}
+
+//Replaces the existing rows that conflict with the rows of an INSERT. See INSERT_REPLACE
+//    //INSERT OR REPLACE INTO `items` (`id`, `name`) VALUES (1, 'Test') //sqlite3
+//    //REPLACE INTO `items` (`id`, `name`) VALUES (1, 'Test') //mysql
+//    db.From("items").Replace().Insert(goqu.Record{"id": 1, "name": "Test"})
+func (me *Dataset) Replace() *Dataset {
+	return me.InsertAs(INSERT_REPLACE)
+}
+
+//Sets the conflict resolution of an INSERT, the adapter returns an error when generating the INSERT if it does not
+//support the variant
+//    //INSERT OR ROLLBACK INTO `items` (`id`, `name`) VALUES (1, 'Test') //sqlite3
+//    db.From("items").InsertAs(goqu.INSERT_ROLLBACK).Insert(goqu.Record{"id": 1, "name": "Test"})
+//
+//variant: The InsertVariant to use, INSERT_DEFAULT for a plain INSERT INTO
+func (me *Dataset) InsertAs(variant InsertVariant) *Dataset {
+	ret := me.copy()
+	ret.clauses.InsertVariant = variant
+	return ret
+}
+
+//Handles rows of an INSERT that conflict with an existing row (e.g. a unique constraint violation) with the given action,
+//making the insert an upsert. See DoNothing, DoUpdate and Excluded
+//    //INSERT INTO "items" ("id", "name") VALUES (1, 'Test') ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name"
+//    db.From("items").OnConflict("id", goqu.DoUpdate(goqu.Record{"name": goqu.Excluded("name")})).Insert(item)
+//    //INSERT INTO "items" ("id", "name") VALUES (1, 'Test') ON CONFLICT DO NOTHING
+//    db.From("items").OnConflict(nil, goqu.DoNothing()).Insert(item)
+//mysql does not support a conflict target, DoNothing (see InsertIgnore) or the conditions of DoUpdate
+//    //INSERT INTO `items` (`id`, `name`) VALUES (1, 'Test') ON DUPLICATE KEY UPDATE `name`=VALUES(`name`)
+//    db.From("items").OnConflict(nil, goqu.DoUpdate(goqu.Record{"name": goqu.Excluded("name")})).Insert(item)
+//
+//target: The columns or constraint that conflict, nil for any conflict. DoUpdate requires a target unless the adapter
+//supports updating without one (see Adapter#SupportsConflictUpdateWithoutTarget). Either a comma separated string of columns
+//(e.g. "user_id, item_id"), an Expression for a single column (e.g. goqu.I("id")) or a LiteralExpression which is used as
+//is (e.g. goqu.L("ON CONSTRAINT items_pkey"))
+//
+//action: The action to take for the conflicting rows, DoNothing() or DoUpdate(...)
+func (me *Dataset) OnConflict(target interface{}, action ConflictAction) *Dataset {
+	ret := me.copy()
+	switch t := target.(type) {
+	case nil:
+		ret.clauses.ConflictTarget = nil
+	case LiteralExpression:
+		ret.clauses.ConflictTarget = t
+	case string:
+		var targetCols []interface{}
+		for _, col := range strings.Split(t, ",") {
+			targetCols = append(targetCols, strings.TrimSpace(col))
+		}
+		ret.clauses.ConflictTarget = cols(targetCols...)
+	default:
+		ret.clauses.ConflictTarget = cols(t)
+	}
+	ret.clauses.ConflictAction = action
+	return ret
+}
+
 //Generates the default INSERT statement. If Prepared has been called with true then the statement will not be interpolated. See examples.
 //When using structs you may specify a column to be skipped in the insert, (e.g. id) by specifying a goqu tag with `skipinsert`
 //    type Item struct{
 //       Id   uint32 `db:"id" goqu:"skipinsert"`
 //       Name string `db:"name"`
 //    }
+//Fields with a goqu tag of `json` (e.g. maps, slices or structs) are inserted as JSON, nil values are inserted as NULL
+//    type Item struct{
+//       Name string            `db:"name"`
+//       Tags map[string]string `db:"tags" goqu:"json"`
+//    }
 //
 //rows: variable number arguments of either map[string]interface, Record, struct, or a single slice argument of the accepted types.
 //
@@ -49,8 +180,70 @@ func (me *Dataset) ToInsertSql(rows ...interface{}) (string, []interface{}, erro
 	return me.insertSql(columns, vals, me.isPrepared)
 }
 
+//used internally to split the rows of an insert into chunks according to the InsertChunkOptions of the dataset. The number
+//of rows derived from the placeholder limit of the adapter assumes each value uses a single placeholder, the placeholders
+//of the ON CONFLICT and RETURNING clauses are reserved for every chunk.
+func (me *Dataset) chunkInsertRows(rows ...interface{}) ([][]interface{}, error) {
+	if len(rows) == 1 {
+		val := reflect.ValueOf(rows[0])
+		if val.Kind() != reflect.Slice {
+			return [][]interface{}{rows}, nil
+		}
+		rows = make([]interface{}, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			rows[i] = val.Index(i).Interface()
+		}
+	}
+	size := me.chunkOpts.Size
+	if size <= 0 {
+		limit := me.adapter.PlaceholderLimit()
+		if !me.isPrepared || limit <= 0 || len(rows) == 0 {
+			return [][]interface{}{rows}, nil
+		}
+		_, vals, err := me.getInsertColsAndVals(rows[0])
+		if err != nil {
+			return nil, err
+		}
+		if len(vals[0]) == 0 {
+			return [][]interface{}{rows}, nil
+		}
+		fixed, err := me.insertClausePlaceholders()
+		if err != nil {
+			return nil, err
+		}
+		if size = (limit - fixed) / len(vals[0]); size <= 0 {
+			return nil, NewGoquError("Unable to insert %d columns with a placeholder limit of %d", len(vals[0]), limit)
+		}
+	}
+	var chunks [][]interface{}
+	for start := 0; start < len(rows); start += size {
+		end := start + size
+		if end > len(rows) {
+			end = len(rows)
+		}
+		chunks = append(chunks, rows[start:end])
+	}
+	return chunks, nil
+}
+
+//used internally to count the placeholders of the clauses that are added to every chunk of an insert independent of
+//its rows (e.g. the updates and conditions of OnConflict)
+func (me *Dataset) insertClausePlaceholders() (int, error) {
+	buf := NewSqlBuilder(true)
+	if err := me.conflictSql(buf); err != nil {
+		return 0, err
+	}
+	if me.adapter.SupportsReturn() {
+		if err := me.adapter.ReturningSql(buf, me.clauses.Returning); err != nil {
+			return 0, err
+		}
+	}
+	_, args := buf.ToSql()
+	return len(args), nil
+}
+
 func (me *Dataset) canInsertField(field reflect.StructField) bool {
-	goquTag, dbTag := tagOptions(field.Tag.Get("goqu")), field.Tag.Get("db")
+	goquTag, dbTag := tagOptions(field.Tag.Get("goqu")), me.fieldColumnName(field)
 	return !goquTag.Contains("skipinsert") && dbTag != "" && dbTag != "-"
 }
 
@@ -99,9 +292,9 @@ func (me *Dataset) getInsertColsAndVals(rows ...interface{}) (columns ColumnList
 				t := newRowValue.Type().Field(j)
 				if me.canInsertField(t) {
 					if columns == nil {
-						rowCols = append(rowCols, t.Tag.Get("db"))
+						rowCols = append(rowCols, me.fieldColumnName(t))
 					}
-					rowVals = append(rowVals, f.Interface())
+					rowVals = append(rowVals, me.fieldValue(t, f))
 				}
 			}
 			if columns == nil {
@@ -118,7 +311,7 @@ func (me *Dataset) getInsertColsAndVals(rows ...interface{}) (columns ColumnList
 //Creates an INSERT statement with the columns and values passed in
 func (me *Dataset) insertSql(cols ColumnList, values [][]interface{}, prepared bool) (string, []interface{}, error) {
 	buf := NewSqlBuilder(prepared)
-	if err := me.adapter.InsertBeginSql(buf); err != nil {
+	if err := me.insertBeginSql(buf); err != nil {
 		return "", nil, err
 	}
 	if err := me.adapter.SourcesSql(buf, me.clauses.From); err != nil {
@@ -133,9 +326,13 @@ func (me *Dataset) insertSql(cols ColumnList, values [][]interface{}, prepared b
 			return "", nil, NewGoquError(err.Error())
 		}
 		if err := me.adapter.InsertValuesSql(buf, values); err != nil {
-			return "", nil, NewGoquError(err.Error())
+			//the values are serialized with Literal which already returns a GoquError
+			return "", nil, err
 		}
 	}
+	if err := me.conflictSql(buf); err != nil {
+		return "", nil, err
+	}
 	if me.adapter.SupportsReturn() {
 		if err := me.adapter.ReturningSql(buf, me.clauses.Returning); err != nil {
 			return "", nil, err
@@ -151,7 +348,7 @@ func (me *Dataset) insertSql(cols ColumnList, values [][]interface{}, prepared b
 //Creates an insert statement with values coming from another dataset
 func (me *Dataset) insertFromSql(other Dataset, prepared bool) (string, []interface{}, error) {
 	buf := NewSqlBuilder(prepared)
-	if err := me.adapter.InsertBeginSql(buf); err != nil {
+	if err := me.insertBeginSql(buf); err != nil {
 		return "", nil, err
 	}
 	if err := me.adapter.SourcesSql(buf, me.clauses.From); err != nil {
@@ -161,6 +358,9 @@ func (me *Dataset) insertFromSql(other Dataset, prepared bool) (string, []interf
 	if err := other.selectSqlWriteTo(buf); err != nil {
 		return "", nil, err
 	}
+	if err := me.conflictSql(buf); err != nil {
+		return "", nil, err
+	}
 	if me.adapter.SupportsReturn() {
 		if err := me.adapter.ReturningSql(buf, me.clauses.Returning); err != nil {
 			return "", nil, err
@@ -171,3 +371,49 @@ func (me *Dataset) insertFromSql(other Dataset, prepared bool) (string, []interf
 	sql, args := buf.ToSql()
 	return sql, args, nil
 }
+
+//Begins an INSERT statement with the InsertVariant of the dataset, validating it against the capabilities of the adapter
+func (me *Dataset) insertBeginSql(buf *SqlBuilder) error {
+	variant := me.clauses.InsertVariant
+	if variant == INSERT_DEFAULT {
+		return me.adapter.InsertBeginSql(buf)
+	}
+	if !me.adapter.SupportsInsertVariant(variant) {
+		return NewGoquError("Adapter does not support %s", variant)
+	}
+	return me.adapter.InsertVariantBeginSql(buf, variant)
+}
+
+//Adds the ON CONFLICT clause of the dataset to an INSERT statement, validating it against the capabilities of the adapter
+func (me *Dataset) conflictSql(buf *SqlBuilder) error {
+	action := me.clauses.ConflictAction
+	if action == nil {
+		return nil
+	}
+	if me.clauses.ConflictTarget != nil && !me.adapter.SupportsConflictTarget() {
+		return NewGoquError("Adapter does not support a conflict target")
+	}
+	if action.IsDoNothing() && !me.adapter.SupportsConflictDoNothing() {
+		return NewGoquError("Adapter does not support DO NOTHING on conflict")
+	}
+	if action.Where() != nil && !me.adapter.SupportsConflictUpdateWhere() {
+		return NewGoquError("Adapter does not support conditions when updating on conflict")
+	}
+	if me.clauses.ConflictTarget == nil && !action.IsDoNothing() && !me.adapter.SupportsConflictUpdateWithoutTarget() {
+		return NewGoquError("Adapter requires a conflict target when updating on conflict")
+	}
+	if err := me.adapter.OnConflictSql(buf, me.clauses.ConflictTarget); err != nil {
+		return err
+	}
+	if action.IsDoNothing() {
+		return me.adapter.ConflictDoNothingSql(buf)
+	}
+	if action.Updates() == nil {
+		return NewGoquError("No update values provided")
+	}
+	updates, err := me.getUpdateExpressions(action.Updates())
+	if err != nil {
+		return err
+	}
+	return me.adapter.ConflictDoUpdateSql(buf, updates, action.Where())
+}
@@ -53,6 +53,82 @@ func (me *datasetTest) TestInsertSqlWithStructs() {
 	assert.Equal(t, sql, `INSERT INTO "items" ("address", "name") VALUES ('111 Test Addr', 'Test1'), ('211 Test Addr', 'Test2'), ('311 Test Addr', 'Test3'), ('411 Test Addr', 'Test4')`)
 }
 
+func (me *datasetTest) TestInsertSqlWithNameMapper() {
+	t := me.T()
+	mDb, _ := sqlmock.New()
+	db := New("mock", mDb)
+	type base struct {
+		Id int64 `db:"id"`
+	}
+	type item struct {
+		base
+		UserID    int64
+		CreatedAt string
+		Name      string `db:"item_name"`
+		Skipped   string `db:"-"`
+		internal  string
+	}
+	i := item{base: base{Id: 1}, UserID: 10, CreatedAt: "2016-01-01", Name: "Test", internal: "internal"}
+	sql, _, err := db.From("items").ToInsertSql(i)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("item_name") VALUES ('Test')`)
+
+	db.NameMapper(SnakeCaseMapper())
+	sql, _, err = db.From("items").ToInsertSql(i)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("user_id", "created_at", "item_name") VALUES (10, '2016-01-01', 'Test')`)
+}
+
+func (me *datasetTest) TestInsertSqlWithJsonTag() {
+	t := me.T()
+	mDb, _ := sqlmock.New()
+	db := New("mock", mDb)
+	type address struct {
+		City string `json:"city"`
+	}
+	type item struct {
+		Name    string            `db:"name"`
+		Tags    []string          `db:"tags" goqu:"json"`
+		Attrs   map[string]string `db:"attrs" goqu:"json"`
+		Address address           `db:"address" goqu:"json"`
+		Prev    *address          `db:"prev" goqu:"json"`
+	}
+	i := item{Name: "Test", Tags: []string{"a", "b's"}, Address: address{City: "Boise"}}
+	sql, _, err := db.From("items").ToInsertSql(i)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("name", "tags", "attrs", "address", "prev") VALUES ('Test', '["a","b''s"]', NULL, '{"city":"Boise"}', NULL)`)
+
+	i.Attrs, i.Prev = map[string]string{"a": "b"}, &address{City: "Reno"}
+	sql, args, err := db.From("items").Prepared(true).ToInsertSql(i)
+	assert.NoError(t, err)
+	assert.Equal(t, args, []interface{}{"Test", `["a","b's"]`, `{"a":"b"}`, `{"city":"Boise"}`, `{"city":"Reno"}`})
+	assert.Equal(t, sql, `INSERT INTO "items" ("name", "tags", "attrs", "address", "prev") VALUES (?, ?, ?, ?, ?)`)
+
+	type unsupported struct {
+		Ch chan int `db:"ch" goqu:"json"`
+	}
+	_, _, err = db.From("items").ToInsertSql(unsupported{Ch: make(chan int)})
+	assert.EqualError(t, err, "goqu: Unable to encode JSON value: json: unsupported type: chan int")
+}
+
+func (me *datasetTest) TestInsertSqlWithSliceFields() {
+	t := me.T()
+	mDb, _ := sqlmock.New()
+	db := New("mock", mDb)
+	type item struct {
+		Name  string   `db:"name"`
+		Tags  []string `db:"tags"`
+		Bytes []byte   `db:"bytes"`
+	}
+	sql, _, err := db.From("items").ToInsertSql(item{Name: "Test", Tags: []string{"a", "b"}, Bytes: []byte("c")})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("name", "tags", "bytes") VALUES ('Test', ARRAY['a', 'b'], 'c')`)
+
+	sql, _, err = db.From("items").ToUpdateSql(item{Name: "Test"})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `UPDATE "items" SET "name"='Test',"tags"=NULL,"bytes"=''`)
+}
+
 func (me *datasetTest) TestInsertSqlWithMaps() {
 	t := me.T()
 	ds1 := From("items")
@@ -373,3 +449,193 @@ func (me *datasetTest) TestPreparedInsertDefaultValues() {
 	assert.Equal(t, sql, `INSERT INTO "items" ("address", "name") VALUES (DEFAULT, DEFAULT)`)
 
 }
+
+func (me *datasetTest) TestChunkInserts() {
+	t := me.T()
+	mDb, _ := sqlmock.New()
+	ds1 := New("placeholder-limit", mDb).From("items")
+	rows := []Record{
+		{"address": "111 Test Addr", "name": "Test1"},
+		{"address": "211 Test Addr", "name": "Test2"},
+		{"address": "311 Test Addr", "name": "Test3"},
+		{"address": "411 Test Addr", "name": "Test4"},
+		{"address": "511 Test Addr", "name": "Test5"},
+	}
+
+	exec := ds1.Prepared(true).ChunkInserts(InsertChunkOptions{}).Insert(rows)
+	assert.Len(t, exec.chunks, 3)
+	assert.Equal(t, exec.Sql, `INSERT INTO "items" ("address", "name") VALUES (?, ?), (?, ?)`)
+	assert.Equal(t, exec.Args, []interface{}{"111 Test Addr", "Test1", "211 Test Addr", "Test2"})
+	assert.Equal(t, exec.chunks[1].Sql, `INSERT INTO "items" ("address", "name") VALUES (?, ?), (?, ?)`)
+	assert.Equal(t, exec.chunks[1].Args, []interface{}{"311 Test Addr", "Test3", "411 Test Addr", "Test4"})
+	assert.Equal(t, exec.chunks[2].Sql, `INSERT INTO "items" ("address", "name") VALUES (?, ?)`)
+	assert.Equal(t, exec.chunks[2].Args, []interface{}{"511 Test Addr", "Test5"})
+
+	exec = ds1.ChunkInserts(InsertChunkOptions{Size: 3}).Insert(rows[0], rows[1], rows[2], rows[3], rows[4])
+	assert.Len(t, exec.chunks, 2)
+	assert.Equal(t, exec.chunks[0].Sql, `INSERT INTO "items" ("address", "name") VALUES ('111 Test Addr', 'Test1'), ('211 Test Addr', 'Test2'), ('311 Test Addr', 'Test3')`)
+	assert.Equal(t, exec.chunks[1].Sql, `INSERT INTO "items" ("address", "name") VALUES ('411 Test Addr', 'Test4'), ('511 Test Addr', 'Test5')`)
+
+	//the placeholder limit only applies to prepared statements
+	exec = ds1.ChunkInserts(InsertChunkOptions{}).Insert(rows)
+	assert.Len(t, exec.chunks, 0)
+	assert.Equal(t, exec.Sql, `INSERT INTO "items" ("address", "name") VALUES ('111 Test Addr', 'Test1'), ('211 Test Addr', 'Test2'), ('311 Test Addr', 'Test3'), ('411 Test Addr', 'Test4'), ('511 Test Addr', 'Test5')`)
+
+	exec = ds1.Prepared(true).ChunkInserts(InsertChunkOptions{}).Insert(rows[0])
+	assert.Len(t, exec.chunks, 0)
+	assert.Equal(t, exec.Sql, `INSERT INTO "items" ("address", "name") VALUES (?, ?)`)
+
+	_, err := ds1.Prepared(true).ChunkInserts(InsertChunkOptions{}).Insert([]Record{
+		{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6},
+		{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6},
+	}).Exec()
+	assert.EqualError(t, err, "goqu: Unable to insert 6 columns with a placeholder limit of 5")
+	_, err = ds1.ChunkInserts(InsertChunkOptions{Size: 1}).Insert([]interface{}{rows[0], true}).Exec()
+	assert.EqualError(t, err, "goqu: Unsupported insert must be map, goqu.Record, or struct type got: bool")
+}
+
+func (me *datasetTest) TestChunkInsertsOnConflict() {
+	t := me.T()
+	mDb, _ := sqlmock.New()
+	ds1 := New("placeholder-limit", mDb).From("items").Prepared(true)
+	rows := []Record{
+		{"address": "111 Test Addr", "name": "Test1"},
+		{"address": "211 Test Addr", "name": "Test2"},
+		{"address": "311 Test Addr", "name": "Test3"},
+	}
+
+	//the update value and condition use two placeholders in every chunk leaving room for a single row
+	exec := ds1.ChunkInserts(InsertChunkOptions{}).
+		OnConflict("name", DoUpdate(Record{"address": "Unknown"}, I("items.id").Gt(10))).
+		Returning("id").
+		Insert(rows)
+	assert.Len(t, exec.chunks, 3)
+	assert.Equal(t, exec.Sql, `INSERT INTO "items" ("address", "name") VALUES (?, ?) ON CONFLICT ("name") DO UPDATE SET "address"=? WHERE ("items"."id" > ?) RETURNING "id"`)
+	assert.Equal(t, exec.Args, []interface{}{"111 Test Addr", "Test1", "Unknown", int64(10)})
+
+	exec = ds1.ChunkInserts(InsertChunkOptions{}).OnConflict("name", DoNothing()).Insert(rows)
+	assert.Len(t, exec.chunks, 2)
+	assert.Equal(t, exec.Sql, `INSERT INTO "items" ("address", "name") VALUES (?, ?), (?, ?) ON CONFLICT ("name") DO NOTHING`)
+
+	_, err := ds1.ChunkInserts(InsertChunkOptions{}).
+		OnConflict("name", DoUpdate(Record{"address": "Unknown", "name": "Unknown"}, I("items.address").Neq("Known"), I("items.id").Gt(10))).
+		Insert(rows).
+		Exec()
+	assert.EqualError(t, err, "goqu: Unable to insert 2 columns with a placeholder limit of 5")
+}
+
+
+func (me *datasetTest) TestInsertSqlOnConflict() {
+	t := me.T()
+	ds1 := From("items")
+	item := Record{"id": 1, "name": "Test"}
+
+	sql, _, err := ds1.OnConflict(nil, DoNothing()).ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("id", "name") VALUES (1, 'Test') ON CONFLICT DO NOTHING`)
+
+	sql, _, err = ds1.OnConflict("id", DoNothing()).ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("id", "name") VALUES (1, 'Test') ON CONFLICT ("id") DO NOTHING`)
+
+	sql, _, err = ds1.OnConflict("user_id, item_id", DoNothing()).ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("id", "name") VALUES (1, 'Test') ON CONFLICT ("user_id", "item_id") DO NOTHING`)
+
+	sql, _, err = ds1.OnConflict(L("ON CONSTRAINT items_pkey"), DoNothing()).ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("id", "name") VALUES (1, 'Test') ON CONFLICT ON CONSTRAINT items_pkey DO NOTHING`)
+
+	sql, _, err = ds1.OnConflict(I("id"), DoUpdate(Record{"name": Excluded("name")})).ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("id", "name") VALUES (1, 'Test') ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name"`)
+
+	sql, _, err = ds1.OnConflict("id", DoUpdate(
+		Record{"count": L("? + ?", I("items.count"), Excluded("count")), "name": "Test2"},
+		I("items.locked").IsFalse(),
+		I("items.name").Neq(Excluded("name")),
+	)).Returning("id").ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("id", "name") VALUES (1, 'Test') ON CONFLICT ("id") DO UPDATE SET "count"="items"."count" + EXCLUDED."count","name"='Test2' WHERE (("items"."locked" IS FALSE) AND ("items"."name" != EXCLUDED."name")) RETURNING "id"`)
+
+	type conflictItem struct {
+		Id   int64  `db:"id" goqu:"skipupdate"`
+		Name string `db:"name"`
+	}
+	sql, _, err = ds1.OnConflict("id", DoUpdate(conflictItem{Id: 1, Name: "Test2"})).ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("id", "name") VALUES (1, 'Test') ON CONFLICT ("id") DO UPDATE SET "name"='Test2'`)
+
+	sql, _, err = ds1.OnConflict("id", DoNothing()).ToInsertSql(From("other_items"))
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" SELECT * FROM "other_items" ON CONFLICT ("id") DO NOTHING`)
+
+	_, _, err = ds1.OnConflict("id", DoUpdate(nil)).ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: No update values provided")
+	_, _, err = ds1.OnConflict("id", DoUpdate(Record{})).ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: No update values provided")
+	_, _, err = ds1.OnConflict("id", DoUpdate(true)).ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: Unsupported update interface type bool")
+
+	sql, _, err = ds1.OnConflict(nil, DoNothing()).ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("id", "name") VALUES (1, 'Test') ON CONFLICT DO NOTHING`)
+	_, _, err = ds1.OnConflict(nil, DoUpdate(Record{"name": Excluded("name")})).ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: Adapter requires a conflict target when updating on conflict")
+}
+
+func (me *datasetTest) TestPreparedInsertSqlOnConflict() {
+	t := me.T()
+	ds1 := From("items")
+
+	sql, args, err := ds1.Prepared(true).
+		OnConflict("id", DoUpdate(Record{"name": Excluded("name"), "count": 0}, I("items.count").Gt(10))).
+		ToInsertSql(Record{"id": 1, "name": "Test"})
+	assert.NoError(t, err)
+	assert.Equal(t, args, []interface{}{int64(1), "Test", int64(0), int64(10)})
+	assert.Equal(t, sql, `INSERT INTO "items" ("id", "name") VALUES (?, ?) ON CONFLICT ("id") DO UPDATE SET "count"=?,"name"=EXCLUDED."name" WHERE ("items"."count" > ?)`)
+}
+
+func (me *datasetTest) TestInsertSqlOnConflictNotSupported() {
+	t := me.T()
+	mDb, _ := sqlmock.New()
+	ds1 := New("no-conflict", mDb).From("items")
+	item := Record{"id": 1, "name": "Test"}
+
+	sql, _, err := ds1.OnConflict(nil, DoUpdate(Record{"name": Excluded("name")})).ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("id", "name") VALUES (1, 'Test') ON CONFLICT DO UPDATE SET "name"=EXCLUDED."name"`)
+
+	_, _, err = ds1.OnConflict("id", DoUpdate(Record{"name": Excluded("name")})).ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: Adapter does not support a conflict target")
+	_, _, err = ds1.OnConflict(nil, DoNothing()).ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: Adapter does not support DO NOTHING on conflict")
+	_, _, err = ds1.OnConflict(nil, DoUpdate(Record{"name": "Test"}, I("locked").IsFalse())).ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: Adapter does not support conditions when updating on conflict")
+}
+
+func (me *datasetTest) TestInsertVariantNotSupported() {
+	t := me.T()
+	ds1 := From("items")
+	item := Record{"id": 1}
+
+	_, _, err := ds1.InsertIgnore().ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: Adapter does not support INSERT IGNORE")
+	_, _, err = ds1.Replace().ToInsertSql(From("other_items"))
+	assert.EqualError(t, err, "goqu: Adapter does not support INSERT REPLACE")
+	_, _, err = ds1.InsertAs(INSERT_ROLLBACK).ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: Adapter does not support INSERT ROLLBACK")
+
+	sql, _, err := ds1.InsertIgnore().InsertAs(INSERT_DEFAULT).ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT INTO "items" ("id") VALUES (1)`)
+
+	adapter := NewDefaultAdapter(ds1).(*DefaultAdapter)
+	adapter.InsertVariantLookup = map[InsertVariant][]byte{INSERT_IGNORE: []byte("INSERT IGNORE INTO")}
+	ds1.SetAdapter(adapter)
+	sql, _, err = ds1.InsertIgnore().ToInsertSql(item)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `INSERT IGNORE INTO "items" ("id") VALUES (1)`)
+	_, _, err = ds1.Replace().ToInsertSql(item)
+	assert.EqualError(t, err, "goqu: Adapter does not support INSERT REPLACE")
+}
@@ -0,0 +1,391 @@
+package goqu
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type (
+	//The metadata of a page selected with Dataset#Page
+	PageInfo struct {
+		//The number of the page, starting at 1
+		Page uint
+		//The maximum number of rows in a page
+		PageSize uint
+		//The total number of rows of the dataset
+		TotalRows int64
+		//The total number of pages
+		TotalPages int64
+		//Set to true if there is a page after this page
+		HasNext bool
+		//Set to true if there is a page before this page
+		HasPrev bool
+	}
+)
+
+func init() {
+	gob.Register(time.Time{})
+}
+
+//Encodes the last seen values of the ORDER BY columns of a Dataset into an opaque cursor that can be passed to SeekAfter
+//or Paginate. The values must be in the same order as the ORDER BY columns.
+//    cursor, err := goqu.EncodeCursor(lastItem.Created, lastItem.Id)
+//
+//values: The values of the ORDER BY columns of the last row of a page, nil values are allowed. Values of named types (e.g.
+//type Status string) are encoded as their underlying type
+func EncodeCursor(values ...interface{}) (string, error) {
+	encoded := make([]interface{}, len(values))
+	for i, value := range values {
+		v, err := cursorValue(reflect.ValueOf(value))
+		if err != nil {
+			return "", err
+		}
+		encoded[i] = v
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(encoded); err != nil {
+		return "", NewGoquError("Unable to encode cursor: %s", err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+//Decodes a cursor created by EncodeCursor or Paginate into the values of the ORDER BY columns.
+func DecodeCursor(cursor string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, NewGoquError("Invalid cursor")
+	}
+	var values []interface{}
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&values); err != nil {
+		return nil, NewGoquError("Invalid cursor")
+	}
+	return values, nil
+}
+
+//Adds a WHERE clause that only matches the rows that come after the row the cursor was created from, given the ORDER BY
+//columns of the Dataset (i.e. keyset pagination). The direction and NullsFirst/NullsLast of each ORDER BY column is
+//honoured, a column without NullsFirst/NullsLast sorts NULLs where the dialect does by default (see
+//Adapter#SortsNullsFirst). If the cursor is empty the Dataset is returned as is.
+//    ds, err := db.From("items").Order(goqu.I("created").Desc(), goqu.I("id").Asc().NullsFirst()).SeekAfter(cursor)
+//    //SELECT * FROM "items" WHERE (("created" < '2016-01-01') OR (("created" = '2016-01-01') AND ("id" > 10))) ORDER BY "created" DESC, "id" ASC NULLS FIRST
+//
+//When every ORDER BY column has the same direction, sorts NULLs first and has a non nil value, a row value comparison is
+//used instead
+//    //SELECT * FROM "items" WHERE ("created", "id") < ('2016-01-01', 10) ORDER BY "created" DESC, "id" DESC
+//
+//cursor: A cursor created by EncodeCursor or returned from Paginate
+func (me *Dataset) SeekAfter(cursor string) (*Dataset, error) {
+	if cursor == "" {
+		return me, nil
+	}
+	values, err := DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	order, err := me.seekOrder()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != len(order) {
+		return nil, NewGoquError("Cursor has %d values but the dataset is ordered by %d columns", len(values), len(order))
+	}
+	return me.Where(seekAfter(order, me.seekNulls(order), values)), nil
+}
+
+//Selects a page of at most pageSize rows after the cursor (see SeekAfter) and scans them into a slice of structs. The
+//returned cursor can be used to select the next page, it is empty when there are no more rows. Like ScanStructs the rows
+//are appended to the slice. Each ORDER BY column must be an identifier that maps to a field of the struct.
+//    var items []Item
+//    ds := db.From("items").Order(goqu.I("id").Asc())
+//    next, err := ds.Paginate(&items, 10, "")
+//    for err == nil && next != "" {
+//        next, err = ds.Paginate(&items, 10, next)
+//    }
+//
+//i: A pointer to a slice of structs
+//
+//pageSize: The maximum number of rows in the page
+//
+//cursor: The cursor returned for the previous page, empty for the first page
+func (me *Dataset) Paginate(i interface{}, pageSize uint, cursor string) (string, error) {
+	if pageSize == 0 {
+		return "", NewGoquError("Page size must be greater than 0")
+	}
+	order, err := me.seekOrder()
+	if err != nil {
+		return "", err
+	}
+	ds, err := me.SeekAfter(cursor)
+	if err != nil {
+		return "", err
+	}
+	//rows are appended to the slice so only count the rows of this page
+	start := 0
+	if val := reflect.Indirect(reflect.ValueOf(i)); val.Kind() == reflect.Slice {
+		start = val.Len()
+	}
+	if err := ds.Limit(pageSize).ScanStructs(i); err != nil {
+		return "", err
+	}
+	val := reflect.Indirect(reflect.ValueOf(i))
+	if uint(val.Len()-start) < pageSize {
+		return "", nil
+	}
+	return cursorFromStruct(order, reflect.Indirect(val.Index(val.Len()-1)), me.nameMapper())
+}
+
+//Set to true to select the total count and the rows of a page in a single transaction when calling Page, so the count is
+//consistent with the rows. If the dataset was created from a TxDatabase both queries are always executed within its
+//transaction.
+func (me *Dataset) PageInTx(inTx bool) *Dataset {
+	ret := me.copy()
+	ret.pageInTx = inTx
+	return ret
+}
+
+//Selects a page of rows using LIMIT and OFFSET and scans them into a slice of structs, the returned PageInfo contains the
+//total number of rows and pages of the dataset. Grouped, distinct and compound datasets are counted using a sub select.
+//Like ScanStructs the rows are appended to the slice.
+//    var items []Item
+//    page, err := db.From("items").Order(goqu.I("id").Asc()).Page(2, 10, &items)
+//    //SELECT COUNT(*) AS "count" FROM "items"
+//    //SELECT * FROM "items" ORDER BY "id" ASC LIMIT 10 OFFSET 10
+//
+//pageNum: The number of the page to select, starting at 1
+//
+//pageSize: The maximum number of rows in the page
+//
+//i: A pointer to a slice of structs
+func (me *Dataset) Page(pageNum, pageSize uint, i interface{}) (*PageInfo, error) {
+	if pageNum == 0 {
+		return nil, NewGoquError("Page number must be greater than 0")
+	}
+	if pageSize == 0 {
+		return nil, NewGoquError("Page size must be greater than 0")
+	}
+	if db, ok := me.database.(*Database); ok && me.pageInTx {
+		//rows are appended to the slice so remove the rows scanned by a retried transaction
+		val := reflect.Indirect(reflect.ValueOf(i))
+		start := -1
+		if val.Kind() == reflect.Slice {
+			start = val.Len()
+		}
+		var info *PageInfo
+		err := db.WithTxContext(me.Context(), nil, func(tx *TxDatabase) error {
+			if start >= 0 {
+				val.SetLen(start)
+			}
+			ds := me.copy()
+			ds.database = tx
+			var err error
+			info, err = ds.page(pageNum, pageSize, i)
+			return err
+		})
+		return info, err
+	}
+	return me.page(pageNum, pageSize, i)
+}
+
+//used internally to count the rows of the dataset and select the rows of the page
+func (me *Dataset) page(pageNum, pageSize uint, i interface{}) (*PageInfo, error) {
+	total, err := me.ClearOrder().ClearLimit().ClearOffset().countRows()
+	if err != nil {
+		return nil, err
+	}
+	info := &PageInfo{Page: pageNum, PageSize: pageSize, TotalRows: total}
+	info.TotalPages = (total + int64(pageSize) - 1) / int64(pageSize)
+	info.HasNext = int64(pageNum) < info.TotalPages
+	info.HasPrev = pageNum > 1
+	offset := (pageNum - 1) * pageSize
+	if int64(offset) >= total {
+		//the page is past the last row so there is nothing to select
+		return info, nil
+	}
+	if err := me.Limit(pageSize).Offset(offset).ScanStructs(i); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+//used internally to get the ORDER BY columns of the dataset
+func (me *Dataset) seekOrder() ([]OrderedExpression, error) {
+	if me.clauses.Order == nil || len(me.clauses.Order.Columns()) == 0 {
+		return nil, NewGoquError("Keyset pagination requires an ORDER BY clause")
+	}
+	cols := me.clauses.Order.Columns()
+	order := make([]OrderedExpression, len(cols))
+	for i, col := range cols {
+		order[i] = col.(OrderedExpression)
+	}
+	return order, nil
+}
+
+//used internally to resolve where NULLs sort for each ORDER BY column, a column without NullsFirst/NullsLast uses the
+//default of the dialect
+func (me *Dataset) seekNulls(order []OrderedExpression) []null_sort_type {
+	nulls := make([]null_sort_type, len(order))
+	for i, o := range order {
+		switch {
+		case o.NullSortType() != NO_NULLS:
+			nulls[i] = o.NullSortType()
+		case me.adapter.SortsNullsFirst() == (o.Direction() == SORT_ASC):
+			nulls[i] = NULLS_FIRST
+		default:
+			nulls[i] = NULLS_LAST
+		}
+	}
+	return nulls
+}
+
+//used internally to create the cursor for the ORDER BY column values of the last struct in a page
+func cursorFromStruct(order []OrderedExpression, val reflect.Value, mapper NameMapper) (string, error) {
+	cm, err := getColumnMap(val.Addr().Interface(), mapper)
+	if err != nil {
+		return "", err
+	}
+	values := make([]interface{}, len(order))
+	for i, o := range order {
+		ident, ok := o.SortExpression().(IdentifierExpression)
+		if !ok {
+			return "", NewGoquError("Unable to paginate on ORDER BY expression %T, it must be an identifier", o.SortExpression())
+		}
+		col, _ := ident.GetCol().(string)
+		data, ok := cm[strings.ToLower(col)]
+		if !ok {
+			data, ok = cm[col]
+		}
+		if !ok || data.Transient {
+			return "", NewGoquError("Unable to find a struct field for ORDER BY column %s", col)
+		}
+		value, err := cursorValue(fieldByIndex(val, data.FieldIndex))
+		if err != nil {
+			return "", err
+		}
+		values[i] = value
+	}
+	return EncodeCursor(values...)
+}
+
+//used internally to convert a struct field into a value that can be encoded in a cursor. Only registered types can be
+//gob encoded within an interface so values of named types are converted to their underlying type.
+func cursorValue(field reflect.Value) (interface{}, error) {
+	if !field.IsValid() {
+		//the field is in a nil nested struct
+		return nil, nil
+	}
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil, nil
+		}
+		field = field.Elem()
+	}
+	value := field.Interface()
+	if valuer, ok := value.(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	if field.Type().PkgPath() == "" {
+		return value, nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), nil
+	case reflect.Bool:
+		return field.Bool(), nil
+	}
+	return value, nil
+}
+
+//used internally to create the predicate that matches the rows after the values
+func seekAfter(order []OrderedExpression, nulls []null_sort_type, values []interface{}) Expression {
+	if canSeekWithRowValue(order, nulls, values) {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(order)), ", ")
+		args := make([]interface{}, 0, len(order)*2)
+		for _, o := range order {
+			args = append(args, o.SortExpression())
+		}
+		args = append(args, values...)
+		op := ">"
+		if order[0].Direction() == SORT_DESC {
+			op = "<"
+		}
+		return L(fmt.Sprintf("(%s) %s (%s)", placeholders, op, placeholders), args...)
+	}
+	var ors []Expression
+	for i, o := range order {
+		after := seekAfterColumn(o, nulls[i], values[i])
+		if after == nil {
+			continue
+		}
+		ands := make([]Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, seekEqual(order[j].SortExpression(), values[j]))
+		}
+		if len(ands) == 0 {
+			ors = append(ors, after)
+		} else {
+			ors = append(ors, And(append(ands, after)...))
+		}
+	}
+	if len(ors) == 0 {
+		//nothing can come after the values so match no rows
+		return L("1 = 0")
+	}
+	return Or(ors...)
+}
+
+//used internally to check if a row value comparison can be used, which requires every column to have the same direction,
+//sort NULLs first and have a non nil value. A row value comparison never matches NULLs so they must not come after the
+//values.
+func canSeekWithRowValue(order []OrderedExpression, nulls []null_sort_type, values []interface{}) bool {
+	if len(order) < 2 {
+		return false
+	}
+	for i, o := range order {
+		if o.Direction() != order[0].Direction() || nulls[i] != NULLS_FIRST || values[i] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+//used internally to create the predicate that matches the values of a single column that come after the value, nil
+//is returned if no values come after it
+func seekAfterColumn(o OrderedExpression, nulls null_sort_type, value interface{}) Expression {
+	exp := o.SortExpression()
+	if value == nil {
+		if nulls == NULLS_FIRST {
+			return isNot(exp, nil)
+		}
+		return nil
+	}
+	var after Expression
+	if o.Direction() == SORT_DESC {
+		after = lt(exp, value)
+	} else {
+		after = gt(exp, value)
+	}
+	if nulls == NULLS_LAST {
+		return Or(after, is(exp, nil))
+	}
+	return after
+}
+
+//used internally to create the predicate that matches a column with the same value
+func seekEqual(exp Expression, value interface{}) Expression {
+	if value == nil {
+		return is(exp, nil)
+	}
+	return eq(exp, value)
+}
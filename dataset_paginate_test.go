@@ -0,0 +1,269 @@
+package goqu
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type dsTestPaginateItem struct {
+	Id   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func (me *datasetTest) TestEncodeCursor() {
+	t := me.T()
+	created := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	cursor, err := EncodeCursor(created, nil, int64(10), "a")
+	assert.NoError(t, err)
+	values, err := DecodeCursor(cursor)
+	assert.NoError(t, err)
+	assert.Equal(t, values, []interface{}{created, nil, int64(10), "a"})
+
+	type status string
+	type rank uint8
+	type ratio float32
+	cursor, err = EncodeCursor(status("active"), rank(2), ratio(0.5), &created)
+	assert.NoError(t, err)
+	values, err = DecodeCursor(cursor)
+	assert.NoError(t, err)
+	assert.Equal(t, values, []interface{}{"active", uint64(2), float64(0.5), created})
+
+	_, err = DecodeCursor("not a cursor")
+	assert.EqualError(t, err, "goqu: Invalid cursor")
+	_, err = DecodeCursor("bm90IGEgY3Vyc29y")
+	assert.EqualError(t, err, "goqu: Invalid cursor")
+}
+
+func (me *datasetTest) TestSeekAfter() {
+	t := me.T()
+	ds := From("items")
+	cursor, err := EncodeCursor(int64(10))
+	assert.NoError(t, err)
+
+	_, err = ds.SeekAfter(cursor)
+	assert.EqualError(t, err, "goqu: Keyset pagination requires an ORDER BY clause")
+	_, err = ds.Order(I("id").Asc(), I("name").Asc()).SeekAfter(cursor)
+	assert.EqualError(t, err, "goqu: Cursor has 1 values but the dataset is ordered by 2 columns")
+
+	seek, err := ds.Order(I("id").Asc()).SeekAfter("")
+	assert.NoError(t, err)
+	sql, _, err := seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT * FROM "items" ORDER BY "id" ASC`)
+
+	seek, err = ds.Where(I("a").Eq(1)).Order(I("id").Asc()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, _, err = seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE (("a" = 1) AND (("id" > 10) OR ("id" IS NULL))) ORDER BY "id" ASC`)
+
+	seek, err = ds.Order(I("id").Desc()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, args, err := seek.Prepared(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, args, []interface{}{int64(10)})
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE ("id" < ?) ORDER BY "id" DESC`)
+}
+
+func (me *datasetTest) TestSeekAfter_RowValue() {
+	t := me.T()
+	ds := From("items")
+	cursor, err := EncodeCursor("Test1", int64(10))
+	assert.NoError(t, err)
+
+	seek, err := ds.Order(I("name").Asc().NullsFirst(), I("id").Asc().NullsFirst()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, _, err := seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE ("name", "id") > ('Test1', 10) ORDER BY "name" ASC NULLS FIRST, "id" ASC NULLS FIRST`)
+
+	//NULLs sort last in ascending order by default so a row value comparison would skip them
+	seek, err = ds.Order(I("name").Asc(), I("id").Asc()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, _, err = seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE ((("name" > 'Test1') OR ("name" IS NULL)) OR (("name" = 'Test1') AND (("id" > 10) OR ("id" IS NULL)))) ORDER BY "name" ASC, "id" ASC`)
+
+	seek, err = ds.Order(I("name").Desc().NullsLast(), I("id").Desc()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, _, err = seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE ((("name" < 'Test1') OR ("name" IS NULL)) OR (("name" = 'Test1') AND ("id" < 10))) ORDER BY "name" DESC NULLS LAST, "id" DESC`)
+
+	seek, err = ds.Order(I("name").Desc(), I("id").Desc()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, args, err := seek.Prepared(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, args, []interface{}{"Test1", int64(10)})
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE ("name", "id") < (?, ?) ORDER BY "name" DESC, "id" DESC`)
+}
+
+func (me *datasetTest) TestSeekAfter_Expanded() {
+	t := me.T()
+	ds := From("items")
+	cursor, err := EncodeCursor("Test1", int64(10))
+	assert.NoError(t, err)
+
+	seek, err := ds.Order(I("name").Desc(), I("id").Asc()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, _, err := seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE (("name" < 'Test1') OR (("name" = 'Test1') AND (("id" > 10) OR ("id" IS NULL)))) ORDER BY "name" DESC, "id" ASC`)
+
+	seek, err = ds.Order(I("name").Asc().NullsLast(), I("id").Asc()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, _, err = seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE ((("name" > 'Test1') OR ("name" IS NULL)) OR (("name" = 'Test1') AND (("id" > 10) OR ("id" IS NULL)))) ORDER BY "name" ASC NULLS LAST, "id" ASC`)
+
+	cursor, err = EncodeCursor(nil, int64(10))
+	assert.NoError(t, err)
+	seek, err = ds.Order(I("name").Asc().NullsFirst(), I("id").Asc()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, _, err = seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE (("name" IS NOT NULL) OR (("name" IS NULL) AND (("id" > 10) OR ("id" IS NULL)))) ORDER BY "name" ASC NULLS FIRST, "id" ASC`)
+
+	seek, err = ds.Order(I("name").Asc().NullsLast(), I("id").Asc()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, _, err = seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE (("name" IS NULL) AND (("id" > 10) OR ("id" IS NULL))) ORDER BY "name" ASC NULLS LAST, "id" ASC`)
+
+	cursor, err = EncodeCursor(nil)
+	assert.NoError(t, err)
+	seek, err = ds.Order(I("name").Asc().NullsLast()).SeekAfter(cursor)
+	assert.NoError(t, err)
+	sql, _, err = seek.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT * FROM "items" WHERE 1 = 0 ORDER BY "name" ASC NULLS LAST`)
+}
+
+func (me *datasetTest) TestPaginate() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" ORDER BY "name" ASC NULLS FIRST, "id" ASC NULLS FIRST LIMIT 2`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).FromCSVString("1,Test1\n2,Test2"))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" WHERE \("name", "id"\) > \('Test2', 2\) ORDER BY "name" ASC NULLS FIRST, "id" ASC NULLS FIRST LIMIT 2`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).FromCSVString("3,Test3"))
+
+	db := New("mock", mDb)
+	ds := db.From("items").Order(I("name").Asc().NullsFirst(), I("id").Asc().NullsFirst())
+	var items []dsTestPaginateItem
+	next, err := ds.Paginate(&items, 2, "")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, next)
+	values, err := DecodeCursor(next)
+	assert.NoError(t, err)
+	assert.Equal(t, values, []interface{}{"Test2", int64(2)})
+
+	next, err = ds.Paginate(&items, 2, next)
+	assert.NoError(t, err)
+	assert.Empty(t, next)
+	assert.Equal(t, items, []dsTestPaginateItem{{Id: 1, Name: "Test1"}, {Id: 2, Name: "Test2"}, {Id: 3, Name: "Test3"}})
+
+	_, err = ds.Paginate(&items, 0, "")
+	assert.EqualError(t, err, "goqu: Page size must be greater than 0")
+	_, err = db.From("items").Paginate(&items, 2, "")
+	assert.EqualError(t, err, "goqu: Keyset pagination requires an ORDER BY clause")
+}
+
+func (me *datasetTest) TestPaginate_UnmappedOrder() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" ORDER BY "created" ASC LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).FromCSVString("1,Test1"))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" ORDER BY LOWER\(name\) ASC LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).FromCSVString("1,Test1"))
+
+	db := New("mock", mDb)
+	var items []dsTestPaginateItem
+	_, err = db.From("items").Order(I("created").Asc()).Paginate(&items, 1, "")
+	assert.EqualError(t, err, "goqu: Unable to find a struct field for ORDER BY column created")
+	_, err = db.From("items").Order(L("LOWER(name)").Asc()).Paginate(&items, 1, "")
+	assert.EqualError(t, err, "goqu: Unable to paginate on ORDER BY expression goqu.literal, it must be an identifier")
+}
+
+func (me *datasetTest) TestPage() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT COUNT\(\*\) AS "count" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).FromCSVString("5"))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" ORDER BY "id" ASC LIMIT 2 OFFSET 2`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).FromCSVString("3,Test3\n4,Test4"))
+	sqlmock.ExpectQuery(`SELECT COUNT\(\*\) AS "count" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).FromCSVString("5"))
+
+	db := New("mock", mDb)
+	ds := db.From("items").Order(I("id").Asc()).Limit(100)
+	var items []dsTestPaginateItem
+	page, err := ds.Page(2, 2, &items)
+	assert.NoError(t, err)
+	assert.Equal(t, page, &PageInfo{Page: 2, PageSize: 2, TotalRows: 5, TotalPages: 3, HasNext: true, HasPrev: true})
+	assert.Equal(t, items, []dsTestPaginateItem{{Id: 3, Name: "Test3"}, {Id: 4, Name: "Test4"}})
+
+	items = items[0:0]
+	page, err = ds.Page(4, 2, &items)
+	assert.NoError(t, err)
+	assert.Equal(t, page, &PageInfo{Page: 4, PageSize: 2, TotalRows: 5, TotalPages: 3, HasNext: false, HasPrev: true})
+	assert.Len(t, items, 0)
+
+	_, err = ds.Page(0, 2, &items)
+	assert.EqualError(t, err, "goqu: Page number must be greater than 0")
+	_, err = ds.Page(1, 0, &items)
+	assert.EqualError(t, err, "goqu: Page size must be greater than 0")
+}
+
+func (me *datasetTest) TestPage_SubSelect() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT COUNT\(\*\) AS "count" FROM \(SELECT "name" FROM "items" GROUP BY "name"\) AS "t1" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).FromCSVString("0"))
+	sqlmock.ExpectQuery(`SELECT COUNT\(\*\) AS "count" FROM \(SELECT DISTINCT "name" FROM "items" WHERE \("name" != \?\)\) AS "t1" LIMIT \?`).
+		WithArgs("Test1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).FromCSVString("0"))
+
+	db := New("mock", mDb)
+	var items []dsTestPaginateItem
+	page, err := db.From("items").Select("name").GroupBy("name").Page(1, 2, &items)
+	assert.NoError(t, err)
+	assert.Equal(t, page.TotalRows, int64(0))
+	page, err = db.From("items").Prepared(true).SelectDistinct("name").Where(I("name").Neq("Test1")).Page(1, 2, &items)
+	assert.NoError(t, err)
+	assert.Equal(t, page.TotalRows, int64(0))
+}
+
+func (me *datasetTest) TestPage_InTx() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectQuery(`SELECT COUNT\(\*\) AS "count" FROM \(SELECT "name" FROM "items" GROUP BY "name"\) AS "t1"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).FromCSVString("1"))
+	sqlmock.ExpectQuery(`SELECT "name" FROM "items" GROUP BY "name" LIMIT 2`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).FromCSVString("Test1"))
+	sqlmock.ExpectCommit()
+
+	db := New("mock", mDb)
+	var items []dsTestPaginateItem
+	page, err := db.From("items").Select("name").GroupBy("name").PageInTx(true).Page(1, 2, &items)
+	assert.NoError(t, err)
+	assert.Equal(t, page, &PageInfo{Page: 1, PageSize: 2, TotalRows: 1, TotalPages: 1, HasNext: false, HasPrev: false})
+	assert.Equal(t, items, []dsTestPaginateItem{{Name: "Test1"}})
+}
@@ -2,6 +2,7 @@ package goqu
 
 import (
 	"fmt"
+	"strings"
 )
 
 var (
@@ -66,6 +67,36 @@ func (me *Dataset) SelectAppend(selects ...interface{}) *Dataset {
 	return ret
 }
 
+//Selects the columns of the fields of a struct, the columns of a named struct field are qualified by the column name of
+//the field and aliased so they can be scanned into the nested struct. The column name of the field (the db tag or the
+//lower case name of the field) must be the name or alias of the table the columns are selected from. If i is not a struct
+//or a pointer to a struct the error is returned when the SQL is generated (e.g. ToSql or ScanStructs).
+//    type ItemUser struct {
+//        Item Item  `db:"items"`
+//        User *User `db:"users"`
+//    }
+//    db.From("items").Join(goqu.I("users"), goqu.On(goqu.I("users.id").Eq(goqu.I("items.user_id")))).SelectNested(ItemUser{})
+//    //SELECT "items"."id" AS "items.id", "items"."name" AS "items.name", "users"."id" AS "users.id" FROM "items" INNER JOIN "users" ON ("users"."id" = "items"."user_id")
+func (me *Dataset) SelectNested(i interface{}) *Dataset {
+	cm, err := getColumnMap(i, me.nameMapper())
+	if err != nil {
+		ret := me.copy()
+		ret.err = err
+		return ret
+	}
+	var selects []interface{}
+	for _, col := range cm.selectCols() {
+		if j := strings.LastIndex(col, "."); j >= 0 {
+			prefix := col[:j]
+			table := prefix[strings.LastIndex(prefix, ".")+1:]
+			selects = append(selects, identifier{}.Table(table).Col(col[j+1:]).As(identifier{}.Col(col)))
+		} else {
+			selects = append(selects, identifier{}.Col(col))
+		}
+	}
+	return me.Select(selects...)
+}
+
 //Adds a FROM clause. This return a new dataset with the original sources replaced. See examples.
 //You can pass in the following.
 //   string: Will automatically be turned into an identifier
@@ -91,6 +122,9 @@ func (me *Dataset) From(from ...interface{}) *Dataset {
 func (me *Dataset) FromSelf() *Dataset {
 	builder := Dataset{}
 	builder.database = me.database
+	builder.ctx = me.ctx
+	builder.usePrimary = me.usePrimary
+	builder.scanMode = me.scanMode
 	builder.adapter = me.adapter
 	builder.clauses = clauses{
 		Select: cols(Star()),
@@ -348,6 +382,9 @@ func (me *Dataset) ToSql() (string, []interface{}, error) {
 
 //Does actual sql generation of sql, accepts an sql builder so other methods can call when creating subselects and needing prepared sql.
 func (me *Dataset) selectSqlWriteTo(buf *SqlBuilder) error {
+	if me.err != nil {
+		return me.err
+	}
 	if me.clauses.SelectDistinct != nil {
 		if err := me.adapter.SelectDistinctSql(buf, me.clauses.SelectDistinct); err != nil {
 			return err
@@ -1,6 +1,7 @@
 package goqu
 
 import (
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -106,6 +107,34 @@ func (me *datasetTest) TestClearSelect() {
 	assert.Equal(t, sql, `SELECT * FROM "test"`)
 }
 
+func (me *datasetTest) TestSelectNested() {
+	t := me.T()
+	type user struct {
+		Id   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	type itemUser struct {
+		Id    int64  `db:"id"`
+		Name  string `db:"-"`
+		User  *user  `db:"users"`
+		Owner user
+	}
+	ds := From("items").Join(I("users"), On(I("users.id").Eq(I("items.user_id"))))
+	sql, _, err := ds.SelectNested(itemUser{}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `SELECT "id", "users"."id" AS "users.id", "users"."name" AS "users.name", "owner"."id" AS "owner.id", "owner"."name" AS "owner.name" FROM "items" INNER JOIN "users" ON ("users"."id" = "items"."user_id")`)
+
+	_, _, err = ds.SelectNested(10).ToSql()
+	assert.EqualError(t, err, "goqu: Cannot SELECT into this type: int")
+	_, _, err = From("other").Where(I("id").In(ds.SelectNested(10))).ToSql()
+	assert.EqualError(t, err, "goqu: Cannot SELECT into this type: int")
+
+	mDb, _ := sqlmock.New()
+	var items []itemUser
+	err = New("mock", mDb).From("items").SelectNested(10).ScanStructs(&items)
+	assert.EqualError(t, err, "goqu: Cannot SELECT into this type: int")
+}
+
 func (me *datasetTest) TestSelectAppend() {
 	t := me.T()
 	ds1 := From("test")
@@ -1,6 +1,7 @@
 package goqu
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"fmt"
 	"regexp"
@@ -244,6 +245,20 @@ func (me *datasetTest) TestLiteralNilTypes() {
 	assert.NoError(t, ds.Literal(me.Truncate(buf), nil))
 	assert.Equal(t, buf.args, []interface{}{nil})
 	assert.Equal(t, buf.String(), "?")
+
+	var str *string
+	var tm *time.Time
+	var valuer *sql.NullString
+	buf = NewSqlBuilder(false)
+	for _, val := range []interface{}{str, tm, valuer} {
+		assert.NoError(t, ds.Literal(me.Truncate(buf), val))
+		assert.Equal(t, buf.String(), "NULL")
+	}
+
+	buf = NewSqlBuilder(true)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), tm))
+	assert.Equal(t, buf.args, []interface{}{nil})
+	assert.Equal(t, buf.String(), "?")
 }
 
 type datasetValuerType int64
@@ -625,6 +640,59 @@ func (me *datasetTest) TestLiteralCastExpression() {
 	assert.Equal(t, buf.String(), `CAST("a" AS DATE)`)
 }
 
+func (me *datasetTest) TestLiteralArrayExpression() {
+	t := me.T()
+	buf := NewSqlBuilder(false)
+	ds := From("test")
+	assert.NoError(t, ds.Literal(me.Truncate(buf), Array([]string{"a", "b"})))
+	assert.Equal(t, buf.String(), `ARRAY['a', 'b']`)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), Array([][]int64{{1, 2}, {3, 4}})))
+	assert.Equal(t, buf.String(), `ARRAY[ARRAY[1, 2], ARRAY[3, 4]]`)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), I("tags").Contains([]string{"a"})))
+	assert.Equal(t, buf.String(), `("tags" @> ARRAY['a'])`)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), I("tags").ContainedBy(Array([]string{"a"}))))
+	assert.Equal(t, buf.String(), `("tags" <@ ARRAY['a'])`)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), I("tags").Overlaps(I("other_tags"))))
+	assert.Equal(t, buf.String(), `("tags" && "other_tags")`)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), I("id").EqAny([]int64{1, 2})))
+	assert.Equal(t, buf.String(), `("id" = ANY(ARRAY[1, 2]))`)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), L("?", "a").EqAny(I("tags"))))
+	assert.Equal(t, buf.String(), `('a' = ANY("tags"))`)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), Array([]string{"a"}).ContainedBy(I("tags"))))
+	assert.Equal(t, buf.String(), `(ARRAY['a'] <@ "tags")`)
+
+	buf = NewSqlBuilder(true)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), I("tags").Contains([]string{"a", "b"})))
+	assert.Equal(t, buf.args, []interface{}{"a", "b"})
+	assert.Equal(t, buf.String(), `("tags" @> ARRAY[?, ?])`)
+}
+
+func (me *datasetTest) TestInvertedArrayOperators() {
+	t := me.T()
+	//every operator can be inverted and inverting it twice returns the operator
+	for op := EQ_OP; op <= NOT_EQ_ANY_OP; op++ {
+		inverted, ok := operator_inversions[op]
+		assert.True(t, ok, "operator %d has no inversion", op)
+		assert.Equal(t, operator_inversions[inverted], op)
+	}
+
+	buf := NewSqlBuilder(false)
+	ds := From("test")
+	invert := func(exp BooleanExpression) BooleanExpression {
+		return boolean{op: operator_inversions[exp.Op()], lhs: exp.Lhs(), rhs: exp.Rhs()}
+	}
+	assert.NoError(t, ds.Literal(me.Truncate(buf), invert(I("tags").Contains([]string{"a"}))))
+	assert.Equal(t, buf.String(), `NOT ("tags" @> ARRAY['a'])`)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), invert(I("tags").ContainedBy([]string{"a"}))))
+	assert.Equal(t, buf.String(), `NOT ("tags" <@ ARRAY['a'])`)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), invert(I("tags").Overlaps(I("other_tags")))))
+	assert.Equal(t, buf.String(), `NOT ("tags" && "other_tags")`)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), invert(I("id").EqAny([]int64{1, 2}))))
+	assert.Equal(t, buf.String(), `NOT ("id" = ANY(ARRAY[1, 2]))`)
+	assert.NoError(t, ds.Literal(me.Truncate(buf), invert(invert(I("tags").Contains([]string{"a"})))))
+	assert.Equal(t, buf.String(), `("tags" @> ARRAY['a'])`)
+}
+
 func (me *datasetTest) TestCompoundExpression() {
 	t := me.T()
 	buf := NewSqlBuilder(false)
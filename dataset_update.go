@@ -6,7 +6,7 @@ import (
 )
 
 func (me *Dataset) canUpdateField(field reflect.StructField) bool {
-	goquTag, dbTag := tagOptions(field.Tag.Get("goqu")), field.Tag.Get("db")
+	goquTag, dbTag := tagOptions(field.Tag.Get("goqu")), me.fieldColumnName(field)
 	return !goquTag.Contains("skipupdate") && dbTag != "" && dbTag != "-"
 }
 
@@ -17,6 +17,7 @@ func (me *Dataset) canUpdateField(field reflect.StructField) bool {
 //       Created time.Time `db:"created" goqu:"skipupdate"`
 //       Name    string    `db:"name"`
 //    }
+//Fields with a goqu tag of `json` are updated with their JSON encoded value, see ToInsertSql
 //
 //update: can either be a a map[string]interface{}, Record or a struct
 //
@@ -28,25 +29,9 @@ func (me *Dataset) ToUpdateSql(update interface{}) (string, []interface{}, error
 	if !me.hasSources() {
 		return "", nil, NewGoquError("No source found when generating update sql")
 	}
-	updateValue := reflect.Indirect(reflect.ValueOf(update))
-	var updates []UpdateExpression
-	switch updateValue.Kind() {
-	case reflect.Map:
-		keys := valueSlice(updateValue.MapKeys())
-		sort.Sort(keys)
-		for _, key := range keys {
-			updates = append(updates, I(key.String()).Set(updateValue.MapIndex(key).Interface()))
-		}
-	case reflect.Struct:
-		for j := 0; j < updateValue.NumField(); j++ {
-			f := updateValue.Field(j)
-			t := updateValue.Type().Field(j)
-			if me.canUpdateField(t) {
-				updates = append(updates, I(t.Tag.Get("db")).Set(f.Interface()))
-			}
-		}
-	default:
-		return "", nil, NewGoquError("Unsupported update interface type %+v", updateValue.Type())
+	updates, err := me.getUpdateExpressions(update)
+	if err != nil {
+		return "", nil, err
 	}
 	buf := NewSqlBuilder(me.isPrepared)
 	if err := me.adapter.UpdateBeginSql(buf); err != nil {
@@ -81,3 +66,28 @@ func (me *Dataset) ToUpdateSql(update interface{}) (string, []interface{}, error
 	sql, args := buf.ToSql()
 	return sql, args, nil
 }
+
+//used internally to create the UpdateExpressions of an UPDATE statement or an ON CONFLICT DO UPDATE clause
+func (me *Dataset) getUpdateExpressions(update interface{}) ([]UpdateExpression, error) {
+	updateValue := reflect.Indirect(reflect.ValueOf(update))
+	var updates []UpdateExpression
+	switch updateValue.Kind() {
+	case reflect.Map:
+		keys := valueSlice(updateValue.MapKeys())
+		sort.Sort(keys)
+		for _, key := range keys {
+			updates = append(updates, I(key.String()).Set(updateValue.MapIndex(key).Interface()))
+		}
+	case reflect.Struct:
+		for j := 0; j < updateValue.NumField(); j++ {
+			f := updateValue.Field(j)
+			t := updateValue.Type().Field(j)
+			if me.canUpdateField(t) {
+				updates = append(updates, I(me.fieldColumnName(t)).Set(me.fieldValue(t, f)))
+			}
+		}
+	default:
+		return nil, NewGoquError("Unsupported update interface type %+v", updateValue.Type())
+	}
+	return updates, nil
+}
@@ -69,6 +69,52 @@ func (me *datasetTest) TestUpdateSqlWithStructs() {
 	assert.Equal(t, sql, `UPDATE "items" SET "address"='111 Test Addr',"name"='Test'`)
 }
 
+func (me *datasetTest) TestUpdateSqlWithNameMapper() {
+	t := me.T()
+	mDb, _ := sqlmock.New()
+	db := New("mock", mDb)
+	type item struct {
+		Id        int64 `db:"id" goqu:"skipupdate"`
+		UserID    int64
+		CreatedAt string `goqu:"skipupdate"`
+		Name      string `db:"item_name"`
+	}
+	i := item{Id: 1, UserID: 10, CreatedAt: "2016-01-01", Name: "Test"}
+	sql, _, err := db.From("items").ToUpdateSql(i)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `UPDATE "items" SET "item_name"='Test'`)
+
+	db.NameMapper(ExactMapper())
+	sql, _, err = db.From("items").ToUpdateSql(i)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `UPDATE "items" SET "UserID"=10,"item_name"='Test'`)
+}
+
+func (me *datasetTest) TestUpdateSqlWithJsonTag() {
+	t := me.T()
+	mDb, _ := sqlmock.New()
+	db := New("mock", mDb)
+	type address struct {
+		City string `json:"city"`
+	}
+	type item struct {
+		Name    string
+		Tags    []string `goqu:"json"`
+		Address *address `goqu:"json"`
+	}
+	i := item{Name: "Test", Tags: []string{"a"}, Address: &address{City: "Boise"}}
+	db.NameMapper(LowerCaseMapper())
+	sql, _, err := db.From("items").ToUpdateSql(i)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, `UPDATE "items" SET "name"='Test',"tags"='["a"]',"address"='{"city":"Boise"}'`)
+
+	i.Address = nil
+	sql, args, err := db.From("items").Prepared(true).ToUpdateSql(i)
+	assert.NoError(t, err)
+	assert.Equal(t, args, []interface{}{"Test", `["a"]`, nil})
+	assert.Equal(t, sql, `UPDATE "items" SET "name"=?,"tags"=?,"address"=?`)
+}
+
 func (me *datasetTest) TestUpdateSqlWithMaps() {
 	t := me.T()
 	ds1 := From("items")
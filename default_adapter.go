@@ -1,6 +1,8 @@
 package goqu
 
 import (
+	"database/sql/driver"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -52,6 +54,13 @@ var (
 	default_union_all_fragment      = []byte(" UNION ALL ")
 	default_intersect_fragment      = []byte(" INTERSECT ")
 	default_intersect_all_fragment  = []byte(" INTERSECT ALL ")
+	default_savepoint_clause        = []byte("SAVEPOINT ")
+	default_rollback_to_clause      = []byte("ROLLBACK TO SAVEPOINT ")
+	default_release_clause          = []byte("RELEASE SAVEPOINT ")
+	default_on_conflict_fragment    = []byte(" ON CONFLICT")
+	default_do_nothing_fragment     = []byte(" DO NOTHING")
+	default_do_update_fragment      = []byte(" DO UPDATE SET ")
+	default_excluded_fragment       = []byte("EXCLUDED.")
 	default_set_operator_rune       = '='
 	default_string_quote_rune       = '\''
 	default_place_holder_rune       = '?'
@@ -74,6 +83,10 @@ var (
 		REGEXP_NOT_LIKE_OP:   []byte("!~"),
 		REGEXP_I_LIKE_OP:     []byte("~*"),
 		REGEXP_NOT_I_LIKE_OP: []byte("!~*"),
+		CONTAINS_OP:          []byte("@>"),
+		CONTAINED_BY_OP:      []byte("<@"),
+		OVERLAPS_OP:          []byte("&&"),
+		EQ_ANY_OP:            []byte("= ANY"),
 	}
 	default_join_lookup = map[JoinType][]byte{
 		INNER_JOIN:         []byte(" INNER JOIN "),
@@ -166,6 +179,20 @@ type (
 		IntersectFragment []byte
 		//The INTERSECT ALL keyword used when creating compound statements (DEFAULT=[]byte(" INTERSECT ALL "))
 		IntersectAllFragment []byte
+		//The SAVEPOINT fragment used when creating a savepoint in a transaction (DEFAULT=[]byte("SAVEPOINT "))
+		SavepointClause []byte
+		//The ROLLBACK TO fragment used when rolling back to a savepoint (DEFAULT=[]byte("ROLLBACK TO SAVEPOINT "))
+		RollbackToClause []byte
+		//The RELEASE fragment used when releasing a savepoint (DEFAULT=[]byte("RELEASE SAVEPOINT "))
+		ReleaseClause []byte
+		//The fragment used to begin handling conflicts in an INSERT statement (DEFAULT=[]byte(" ON CONFLICT"))
+		OnConflictFragment []byte
+		//The fragment used to leave a conflicting row as is (DEFAULT=[]byte(" DO NOTHING"))
+		ConflictDoNothingFragment []byte
+		//The fragment used to update a conflicting row (DEFAULT=[]byte(" DO UPDATE SET "))
+		ConflictDoUpdateFragment []byte
+		//The fragment used to reference a column of the row proposed for insertion (DEFAULT=[]byte("EXCLUDED."))
+		ExcludedFragment []byte
 		//The quote rune to use when quoting string literals (DEFAULT='\'')
 		StringQuote rune
 		//The operator to use when setting values in an update statement (DEFAULT='=')
@@ -180,56 +207,68 @@ type (
 		BooleanOperatorLookup map[BooleanOperation][]byte
 		//A map used to look up JoinTypes and their SQL equivalents
 		JoinTypeLookup map[JoinType][]byte
+		//A map used to look up the beginning of an INSERT statement for an InsertVariant, a variant that is not in the map is
+		//not supported (DEFAULT=nil)
+		InsertVariantLookup map[InsertVariant][]byte
 		//Whether or not to use literal TRUE or FALSE for IS statements (e.g. IS TRUE or IS 0)
 		UseLiteralIsBools bool
+		//The maximum number of placeholders allowed in a single statement, 0 if there is no limit (DEFAULT=0)
+		MaxPlaceholders int
 	}
 )
 
 func NewDefaultAdapter(ds *Dataset) Adapter {
 	return &DefaultAdapter{
-		dataset:               ds,
-		UpdateClause:          default_update_clause,
-		InsertClause:          default_insert_clause,
-		SelectClause:          default_select_clause,
-		DeleteClause:          default_delete_clause,
-		TruncateClause:        default_truncate_clause,
-		CascadeFragment:       default_cascade_fragment,
-		RestrictFragment:      default_retrict_fragment,
-		DefaultValuesFragment: default_default_values_fragment,
-		ValuesFragment:        default_values_fragment,
-		IdentityFragment:      default_identity_fragment,
-		SetFragment:           default_set_fragment,
-		DistinctFragment:      default_distinct_fragment,
-		ReturningFragment:     default_returning_fragment,
-		FromFragment:          default_from_fragment,
-		WhereFragment:         default_where_fragment,
-		GroupByFragment:       default_group_by_fragment,
-		HavingFragment:        default_having_fragment,
-		OrderByFragment:       default_order_by_fragment,
-		LimitFragment:         default_limit_fragment,
-		OffsetFragment:        default_offset_fragment,
-		AsFragment:            default_as_fragment,
-		QuoteRune:             default_quote,
-		Null:                  default_null,
-		True:                  default_true,
-		False:                 default_false,
-		StringQuote:           default_string_quote_rune,
-		AscFragment:           default_asc_fragment,
-		DescFragment:          default_desc_fragment,
-		NullsFirstFragment:    default_nulls_first_fragment,
-		NullsLastFragment:     default_nulls_last_fragment,
-		AndFragment:           default_and_fragment,
-		OrFragment:            default_or_fragment,
-		SetOperatorRune:       default_set_operator_rune,
-		UnionFragment:         default_union_fragment,
-		UnionAllFragment:      default_union_all_fragment,
-		IntersectFragment:     default_intersect_fragment,
-		IntersectAllFragment:  default_intersect_all_fragment,
-		PlaceHolderRune:       default_place_holder_rune,
-		BooleanOperatorLookup: default_operator_lookup,
-		JoinTypeLookup:        default_join_lookup,
-		TimeFormat:            time.RFC3339Nano,
-		UseLiteralIsBools:     true,
+		dataset:                   ds,
+		UpdateClause:              default_update_clause,
+		InsertClause:              default_insert_clause,
+		SelectClause:              default_select_clause,
+		DeleteClause:              default_delete_clause,
+		TruncateClause:            default_truncate_clause,
+		CascadeFragment:           default_cascade_fragment,
+		RestrictFragment:          default_retrict_fragment,
+		DefaultValuesFragment:     default_default_values_fragment,
+		ValuesFragment:            default_values_fragment,
+		IdentityFragment:          default_identity_fragment,
+		SetFragment:               default_set_fragment,
+		DistinctFragment:          default_distinct_fragment,
+		ReturningFragment:         default_returning_fragment,
+		FromFragment:              default_from_fragment,
+		WhereFragment:             default_where_fragment,
+		GroupByFragment:           default_group_by_fragment,
+		HavingFragment:            default_having_fragment,
+		OrderByFragment:           default_order_by_fragment,
+		LimitFragment:             default_limit_fragment,
+		OffsetFragment:            default_offset_fragment,
+		AsFragment:                default_as_fragment,
+		QuoteRune:                 default_quote,
+		Null:                      default_null,
+		True:                      default_true,
+		False:                     default_false,
+		StringQuote:               default_string_quote_rune,
+		AscFragment:               default_asc_fragment,
+		DescFragment:              default_desc_fragment,
+		NullsFirstFragment:        default_nulls_first_fragment,
+		NullsLastFragment:         default_nulls_last_fragment,
+		AndFragment:               default_and_fragment,
+		OrFragment:                default_or_fragment,
+		SetOperatorRune:           default_set_operator_rune,
+		UnionFragment:             default_union_fragment,
+		UnionAllFragment:          default_union_all_fragment,
+		IntersectFragment:         default_intersect_fragment,
+		IntersectAllFragment:      default_intersect_all_fragment,
+		SavepointClause:           default_savepoint_clause,
+		RollbackToClause:          default_rollback_to_clause,
+		ReleaseClause:             default_release_clause,
+		OnConflictFragment:        default_on_conflict_fragment,
+		ConflictDoNothingFragment: default_do_nothing_fragment,
+		ConflictDoUpdateFragment:  default_do_update_fragment,
+		ExcludedFragment:          default_excluded_fragment,
+		PlaceHolderRune:           default_place_holder_rune,
+		BooleanOperatorLookup:     default_operator_lookup,
+		JoinTypeLookup:            default_join_lookup,
+		TimeFormat:                time.RFC3339Nano,
+		UseLiteralIsBools:         true,
 	}
 }
 
@@ -238,6 +277,17 @@ func (me *DefaultAdapter) SupportsReturn() bool {
 	return true
 }
 
+//Override to select 1 with a LIMIT of 1 instead of SELECT EXISTS(...) when calling Dataset#Exists
+func (me *DefaultAdapter) SupportsSelectExists() bool {
+	return true
+}
+
+//Override if the dialect sorts NULLs first in ascending order, by default NULLs sort as if they are larger than any other
+//value (e.g. postgres)
+func (me *DefaultAdapter) SortsNullsFirst() bool {
+	return false
+}
+
 //Override to allow LIMIT on DELETE statements
 func (me *DefaultAdapter) SupportsLimitOnDelete() bool {
 	return false
@@ -258,6 +308,75 @@ func (me *DefaultAdapter) SupportsOrderByOnUpdate() bool {
 	return false
 }
 
+//Override to prevent a conflict target from being used with Dataset#OnConflict (e.g. mysql)
+func (me *DefaultAdapter) SupportsConflictTarget() bool {
+	return true
+}
+
+//Override to prevent DoNothing from being used with Dataset#OnConflict
+func (me *DefaultAdapter) SupportsConflictDoNothing() bool {
+	return true
+}
+
+//Override to prevent conditions from being passed to DoUpdate when using Dataset#OnConflict
+func (me *DefaultAdapter) SupportsConflictUpdateWhere() bool {
+	return true
+}
+
+//Override to insert and update the slice fields of a struct as is instead of as an ArrayExpression (e.g. mysql, sqlite3)
+func (me *DefaultAdapter) SupportsArrays() bool {
+	return true
+}
+
+//Override to allow DoUpdate to be used with Dataset#OnConflict without a conflict target (e.g. mysql), postgres and
+//sqlite3 require a target for ON CONFLICT DO UPDATE
+func (me *DefaultAdapter) SupportsConflictUpdateWithoutTarget() bool {
+	return false
+}
+
+//Returns true if the InsertVariant is in the InsertVariantLookup of the adapter
+func (me *DefaultAdapter) SupportsInsertVariant(variant InsertVariant) bool {
+	_, ok := me.InsertVariantLookup[variant]
+	return ok
+}
+
+//Generates the sql to create a SAVEPOINT (e.g. SAVEPOINT "name")
+func (me *DefaultAdapter) SavepointSql(buf *SqlBuilder, name string) error {
+	buf.Write(me.SavepointClause)
+	return me.QuoteIdentifier(buf, I(name))
+}
+
+//Generates the sql to ROLLBACK to a SAVEPOINT (e.g. ROLLBACK TO SAVEPOINT "name")
+func (me *DefaultAdapter) RollbackToSavepointSql(buf *SqlBuilder, name string) error {
+	buf.Write(me.RollbackToClause)
+	return me.QuoteIdentifier(buf, I(name))
+}
+
+//Generates the sql to RELEASE a SAVEPOINT (e.g. RELEASE SAVEPOINT "name")
+func (me *DefaultAdapter) ReleaseSavepointSql(buf *SqlBuilder, name string) error {
+	buf.Write(me.ReleaseClause)
+	return me.QuoteIdentifier(buf, I(name))
+}
+
+//Override to classify driver errors that can be retried by Database#WithTx (e.g. serialization failures and deadlocks)
+func (me *DefaultAdapter) IsRetryableTxError(err error) bool {
+	return false
+}
+
+//Override to classify driver errors by their database specific codes, the default implementation only classifies
+//driver.ErrBadConn as ErrConnectionLost
+func (me *DefaultAdapter) ClassifyError(err error) error {
+	if errors.Is(err, driver.ErrBadConn) {
+		return ErrConnectionLost
+	}
+	return nil
+}
+
+//Returns the MaxPlaceholders of the adapter
+func (me *DefaultAdapter) PlaceholderLimit() int {
+	return me.MaxPlaceholders
+}
+
 //This is a proxy to Dataset.Literal. Used internally to ensure the correct method is called on any subclasses and to prevent duplication of code
 func (me *DefaultAdapter) Literal(buf *SqlBuilder, val interface{}) error {
 	return me.dataset.Literal(buf, val)
@@ -285,6 +404,15 @@ func (me *DefaultAdapter) InsertBeginSql(buf *SqlBuilder) error {
 	return nil
 }
 
+//Adds the fragment of an InsertVariant from the InsertVariantLookup to begin an INSERT statement
+func (me *DefaultAdapter) InsertVariantBeginSql(buf *SqlBuilder, variant InsertVariant) error {
+	if val, ok := me.InsertVariantLookup[variant]; ok {
+		buf.Write(val)
+		return nil
+	}
+	return NewGoquError("Adapter does not support %s", variant)
+}
+
 //Adds the correct fragment to being an DELETE statement
 func (me *DefaultAdapter) DeleteBeginSql(buf *SqlBuilder) error {
 	buf.Write(me.DeleteClause)
@@ -347,6 +475,50 @@ func (me *DefaultAdapter) InsertValuesSql(buf *SqlBuilder, values [][]interface{
 	return nil
 }
 
+//Adds the ON CONFLICT clause to an INSERT statement, a ColumnList target is wrapped in parens any other target (e.g.
+//L("ON CONSTRAINT items_pkey")) is written as is
+//   ON CONFLICT ("id")
+func (me *DefaultAdapter) OnConflictSql(buf *SqlBuilder, target Expression) error {
+	buf.Write(me.OnConflictFragment)
+	if target == nil {
+		return nil
+	}
+	buf.WriteRune(space_rune)
+	if _, ok := target.(ColumnList); !ok {
+		return me.Literal(buf, target)
+	}
+	buf.WriteRune(left_paren_rune)
+	if err := me.Literal(buf, target); err != nil {
+		return err
+	}
+	buf.WriteRune(right_paren_rune)
+	return nil
+}
+
+//Adds the DO NOTHING action to the ON CONFLICT clause of an INSERT statement
+func (me *DefaultAdapter) ConflictDoNothingSql(buf *SqlBuilder) error {
+	buf.Write(me.ConflictDoNothingFragment)
+	return nil
+}
+
+//Adds the DO UPDATE action to the ON CONFLICT clause of an INSERT statement
+//   DO UPDATE SET "name"=EXCLUDED."name" WHERE ("items"."locked" IS FALSE)
+func (me *DefaultAdapter) ConflictDoUpdateSql(buf *SqlBuilder, updates []UpdateExpression, where ExpressionList) error {
+	if len(updates) == 0 {
+		return NewGoquError("No update values provided")
+	}
+	buf.Write(me.ConflictDoUpdateFragment)
+	for i, update := range updates {
+		if i > 0 {
+			buf.WriteRune(comma_rune)
+		}
+		if err := me.Literal(buf, update); err != nil {
+			return err
+		}
+	}
+	return me.WhereSql(buf, where)
+}
+
 //Adds column setters in an update SET clause
 func (me *DefaultAdapter) UpdateExpressionsSql(buf *SqlBuilder, updates ...UpdateExpression) error {
 	if len(updates) == 0 {
@@ -637,6 +809,11 @@ func (me *DefaultAdapter) LiteralString(buf *SqlBuilder, s string) error {
 	return nil
 }
 
+//Generates SQL for a JSON encoded value, by default the JSON is written as a string
+func (me *DefaultAdapter) LiteralJson(buf *SqlBuilder, json []byte) error {
+	return me.Literal(buf, string(json))
+}
+
 //Generates SQL for a slice of values (e.g. []int64{1,2,3,4} -> (1,2,3,4)
 func (me *DefaultAdapter) SliceValueSql(buf *SqlBuilder, slice reflect.Value) error {
 	buf.WriteRune(left_paren_rune)
@@ -664,6 +841,10 @@ func (me *DefaultAdapter) AliasedExpressionSql(buf *SqlBuilder, aliased AliasedE
 
 //Generates SQL for a BooleanExpresion (e.g. I("a").Eq(2) -> "a" = 2)
 func (me *DefaultAdapter) BooleanExpressionSql(buf *SqlBuilder, operator BooleanExpression) error {
+	if op, ok := negated_operators[operator.Op()]; ok {
+		buf.WriteString("NOT ")
+		return me.BooleanExpressionSql(buf, boolean{op: op, lhs: operator.Lhs(), rhs: operator.Rhs()})
+	}
 	buf.WriteRune(left_paren_rune)
 	if err := me.Literal(buf, operator.Lhs()); err != nil {
 		return err
@@ -691,9 +872,18 @@ func (me *DefaultAdapter) BooleanExpressionSql(buf *SqlBuilder, operator Boolean
 			rhs = L("FALSE")
 		}
 	}
-	buf.WriteRune(space_rune)
-	if err := me.Literal(buf, rhs); err != nil {
-		return err
+	if operatorOp == EQ_ANY_OP {
+		//the array of ANY must be in parentheses (e.g. "a" = ANY("b"))
+		buf.WriteRune(left_paren_rune)
+		if err := me.Literal(buf, rhs); err != nil {
+			return err
+		}
+		buf.WriteRune(right_paren_rune)
+	} else {
+		buf.WriteRune(space_rune)
+		if err := me.Literal(buf, rhs); err != nil {
+			return err
+		}
 	}
 	buf.WriteRune(right_paren_rune)
 	return nil
@@ -803,6 +993,34 @@ func (me *DefaultAdapter) SqlFunctionExpressionSql(buf *SqlBuilder, sqlFunc SqlF
 	return me.Literal(buf, sqlFunc.Args())
 }
 
+//Generates SQL for an ArrayExpression, slices within the array are written as nested arrays. When prepared each element
+//is a separate argument.
+//   Array([]string{"a", "b"}) -> ARRAY['a', 'b']
+func (me *DefaultAdapter) ArrayExpressionSql(buf *SqlBuilder, array ArrayExpression) error {
+	buf.WriteString("ARRAY[")
+	for i, value := range array.Values() {
+		if i > 0 {
+			buf.WriteRune(comma_rune)
+			buf.WriteRune(space_rune)
+		}
+		if value != nil && isArrayType(reflect.TypeOf(value)) {
+			value = Array(value)
+		}
+		if err := me.Literal(buf, value); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("]")
+	return nil
+}
+
+//Generates SQL for an ExcludedExpression
+//   Excluded("name") -> EXCLUDED."name"
+func (me *DefaultAdapter) ExcludedExpressionSql(buf *SqlBuilder, excluded ExcludedExpression) error {
+	buf.Write(me.ExcludedFragment)
+	return me.Literal(buf, excluded.Col())
+}
+
 //Generates SQL for a CastExpression
 //   I("a").Cast("NUMERIC") -> CAST("a" AS NUMERIC)
 func (me *DefaultAdapter) CastExpressionSql(buf *SqlBuilder, cast CastExpression) error {
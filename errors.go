@@ -1,6 +1,34 @@
 package goqu
 
-import "fmt"
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+//The categories of errors returned by the driver, use errors.Is to check the category of an error returned by a Database,
+//TxDatabase, Dataset or CrudExec. Errors are classified by the adapter of the dialect, see Adapter#ClassifyError. Each
+//category is a distinct value so an error created with NewGoquError is never mistaken for one.
+//    if _, err := db.From("items").Insert(item).Exec(); errors.Is(err, goqu.ErrUniqueViolation) {
+//        //handle duplicate item
+//    }
+var (
+	//A UNIQUE or PRIMARY KEY constraint was violated
+	ErrUniqueViolation = errors.New("goqu: unique violation")
+	//A FOREIGN KEY constraint was violated
+	ErrForeignKeyViolation = errors.New("goqu: foreign key violation")
+	//A NOT NULL constraint was violated
+	ErrNotNullViolation = errors.New("goqu: not null violation")
+	//The transaction was aborted because of a deadlock
+	ErrDeadlock = errors.New("goqu: deadlock")
+	//The transaction could not be serialized
+	ErrSerializationFailure = errors.New("goqu: serialization failure")
+	//The connection to the database was lost
+	ErrConnectionLost = errors.New("goqu: connection lost")
+	//Returned by single row actions (e.g. ScanStruct, ScanVal) of a Dataset when no row is found, see Dataset#ErrOnNoRows.
+	//It is the same error as sql.ErrNoRows.
+	ErrNoRows = sql.ErrNoRows
+)
 
 func newEncodeError(message string, args ...interface{}) error {
 	return EncodeError{err: "goqu: " + fmt.Sprintf(message, args...)}
@@ -25,4 +53,60 @@ type GoquError struct {
 
 func (me GoquError) Error() string {
 	return me.err
-}
\ No newline at end of file
+}
+
+//An error returned by the driver while executing a statement, the message is the message of the driver error. Use
+//errors.Is to check the category of the error (e.g. ErrUniqueViolation) and errors.As to get the DatabaseError or the
+//driver error.
+//    var dbErr *goqu.DatabaseError
+//    if errors.As(err, &dbErr) {
+//        fmt.Println(dbErr.Sql)
+//    }
+type DatabaseError struct {
+	//The category of the error (e.g. ErrUniqueViolation), nil if the adapter could not classify the error
+	Kind error
+	//The operation that failed, one of EXEC, QUERY, PREPARE, COMMIT or ROLLBACK
+	Op string
+	//The SQL of the statement that failed, empty for COMMIT and ROLLBACK
+	Sql string
+	//The arguments for any placeholder parameters in the SQL
+	Args []interface{}
+	//The error returned by the driver
+	Err error
+}
+
+func (me *DatabaseError) Error() string {
+	return me.Err.Error()
+}
+
+//Returns the error returned by the driver
+func (me *DatabaseError) Unwrap() error {
+	return me.Err
+}
+
+//Returns true if the target is the category of the error
+func (me *DatabaseError) Is(target error) bool {
+	return me.Kind != nil && me.Kind == target
+}
+
+//used internally to wrap an error returned by the driver in a DatabaseError
+func newDatabaseError(adapter Adapter, event *QueryEvent, err error) error {
+	if err == nil {
+		return nil
+	}
+	var dbErr *DatabaseError
+	if errors.As(err, &dbErr) {
+		return err
+	}
+	return &DatabaseError{Kind: adapter.ClassifyError(err), Op: event.Op, Sql: event.Sql, Args: event.Args, Err: err}
+}
+
+//used internally to get the error returned by the driver from a DatabaseError
+func driverError(err error) error {
+	var dbErr *DatabaseError
+	if errors.As(err, &dbErr) {
+		return dbErr.Err
+	}
+	return err
+}
+
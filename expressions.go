@@ -432,6 +432,23 @@ type (
 		//    I("col").NotIn([]string{"a", "b", "c"}) //("col" NOT IN ('a', 'b', 'c'))
 		NotIn(...interface{}) BooleanExpression
 	}
+	//Interface that an expression should implement if it can be used with array operators (e.g. postgres arrays). A slice
+	//passed to these methods is converted to an ArrayExpression
+	ArrayMethods interface {
+		//Creates a Boolean expression checking that an array contains all of the elements of another array
+		//    I("tags").Contains([]string{"a", "b"}) //("tags" @> ARRAY['a', 'b'])
+		Contains(interface{}) BooleanExpression
+		//Creates a Boolean expression checking that all of the elements of an array are contained by another array
+		//    I("tags").ContainedBy([]string{"a", "b"}) //("tags" <@ ARRAY['a', 'b'])
+		ContainedBy(interface{}) BooleanExpression
+		//Creates a Boolean expression checking that an array has any elements in common with another array
+		//    I("tags").Overlaps([]string{"a", "b"}) //("tags" && ARRAY['a', 'b'])
+		Overlaps(interface{}) BooleanExpression
+		//Creates a Boolean expression checking that a value is equal to any of the elements of an array
+		//    I("id").EqAny([]int64{1, 2}) //("id" = ANY(ARRAY[1, 2]))
+		//    L("?", "a").EqAny(I("tags")) //('a' = ANY("tags"))
+		EqAny(interface{}) BooleanExpression
+	}
 	//Interface that an expression should implement if it can be ORDERED.
 	OrderedMethods interface {
 		//Creates an Ordered Expression for sql ASC order
@@ -512,6 +529,7 @@ type (
 		updateMethods
 		DistinctMethods
 		CastMethods
+		ArrayMethods
 		//Returns a new IdentifierExpression with the specified schema
 		Schema(string) IdentifierExpression
 		//Returns the current schema
@@ -662,6 +680,26 @@ func (me identifier) Desc() OrderedExpression                     { return desc(
 func (me identifier) Distinct() SqlFunctionExpression             { return DISTINCT(me) }
 func (me identifier) Cast(t string) CastExpression                { return Cast(me, t) }
 
+//Returns a BooleanExpression for checking that an array contains all of the elements of another array (e.g "tags" @> ARRAY['a'])
+func (me identifier) Contains(val interface{}) BooleanExpression {
+	return arrayOp(CONTAINS_OP, me, val)
+}
+
+//Returns a BooleanExpression for checking that all of the elements of an array are in another array (e.g "tags" <@ ARRAY['a'])
+func (me identifier) ContainedBy(val interface{}) BooleanExpression {
+	return arrayOp(CONTAINED_BY_OP, me, val)
+}
+
+//Returns a BooleanExpression for checking that an array has elements in common with another array (e.g "tags" && ARRAY['a'])
+func (me identifier) Overlaps(val interface{}) BooleanExpression {
+	return arrayOp(OVERLAPS_OP, me, val)
+}
+
+//Returns a BooleanExpression for checking that a value is equal to any of the elements of an array (e.g "id" = ANY(ARRAY[1]))
+func (me identifier) EqAny(val interface{}) BooleanExpression {
+	return arrayOp(EQ_ANY_OP, me, val)
+}
+
 type (
 	//Expression for representing "literal" sql.
 	//  L("col = 1") -> col = 1)
@@ -671,6 +709,7 @@ type (
 		AliasMethods
 		ComparisonMethods
 		OrderedMethods
+		ArrayMethods
 		//Returns the literal sql
 		Literal() string
 		//Arguments to be replaced within the sql
@@ -709,7 +748,7 @@ func Star() LiteralExpression {
 }
 
 func (me literal) Clone() Expression {
-	return Literal(me.literal)
+	return Literal(me.literal, me.args...)
 }
 
 func (me literal) Literal() string {
@@ -731,6 +770,26 @@ func (me literal) Lte(val interface{}) BooleanExpression { return lte(me, val) }
 func (me literal) Asc() OrderedExpression                { return asc(me) }
 func (me literal) Desc() OrderedExpression               { return desc(me) }
 
+//Returns a BooleanExpression for checking that an array contains all of the elements of another array (e.g "tags" @> ARRAY['a'])
+func (me literal) Contains(val interface{}) BooleanExpression {
+	return arrayOp(CONTAINS_OP, me, val)
+}
+
+//Returns a BooleanExpression for checking that all of the elements of an array are in another array (e.g "tags" <@ ARRAY['a'])
+func (me literal) ContainedBy(val interface{}) BooleanExpression {
+	return arrayOp(CONTAINED_BY_OP, me, val)
+}
+
+//Returns a BooleanExpression for checking that an array has elements in common with another array (e.g "tags" && ARRAY['a'])
+func (me literal) Overlaps(val interface{}) BooleanExpression {
+	return arrayOp(OVERLAPS_OP, me, val)
+}
+
+//Returns a BooleanExpression for checking that a value is equal to any of the elements of an array (e.g "id" = ANY(ARRAY[1]))
+func (me literal) EqAny(val interface{}) BooleanExpression {
+	return arrayOp(EQ_ANY_OP, me, val)
+}
+
 type (
 	UpdateExpression interface {
 		Col() IdentifierExpression
@@ -817,6 +876,22 @@ const (
 	REGEXP_I_LIKE_OP
 	//!~*, NOT REGEXP
 	REGEXP_NOT_I_LIKE_OP
+	//@>
+	CONTAINS_OP
+	//<@
+	CONTAINED_BY_OP
+	//&&
+	OVERLAPS_OP
+	//= ANY
+	EQ_ANY_OP
+	//NOT (... @> ...)
+	NOT_CONTAINS_OP
+	//NOT (... <@ ...)
+	NOT_CONTAINED_BY_OP
+	//NOT (... && ...)
+	NOT_OVERLAPS_OP
+	//NOT (... = ANY(...))
+	NOT_EQ_ANY_OP
 )
 
 //used internally for inverting operators
@@ -839,6 +914,23 @@ var operator_inversions = map[BooleanOperation]BooleanOperation{
 	NOT_I_LIKE_OP:        I_LIKE_OP,
 	REGEXP_NOT_LIKE_OP:   REGEXP_LIKE_OP,
 	REGEXP_NOT_I_LIKE_OP: REGEXP_I_LIKE_OP,
+	CONTAINS_OP:          NOT_CONTAINS_OP,
+	CONTAINED_BY_OP:      NOT_CONTAINED_BY_OP,
+	OVERLAPS_OP:          NOT_OVERLAPS_OP,
+	EQ_ANY_OP:            NOT_EQ_ANY_OP,
+	NOT_CONTAINS_OP:      CONTAINS_OP,
+	NOT_CONTAINED_BY_OP:  CONTAINED_BY_OP,
+	NOT_OVERLAPS_OP:      OVERLAPS_OP,
+	NOT_EQ_ANY_OP:        EQ_ANY_OP,
+}
+
+//used internally to write the inversions of the array operators, which have no operator of their own, as the negation of
+//the operator (e.g. NOT ("tags" @> ARRAY['a']))
+var negated_operators = map[BooleanOperation]BooleanOperation{
+	NOT_CONTAINS_OP:     CONTAINS_OP,
+	NOT_CONTAINED_BY_OP: CONTAINED_BY_OP,
+	NOT_OVERLAPS_OP:     OVERLAPS_OP,
+	NOT_EQ_ANY_OP:       EQ_ANY_OP,
 }
 
 func (me boolean) Clone() Expression {
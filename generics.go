@@ -0,0 +1,59 @@
+//go:build go1.18
+// +build go1.18
+
+package goqu
+
+//A Dataset or CrudExec whose rows can be scanned with All, One, Vals and Val
+type Scannable interface {
+	ScanStructs(i interface{}) error
+	ScanStruct(i interface{}) (bool, error)
+	ScanVals(i interface{}) error
+	ScanVal(i interface{}) (bool, error)
+}
+
+//Scans the rows of a Dataset or CrudExec into a slice of structs of type T, see Dataset#ScanStructs
+//    users, err := goqu.All[User](db.From("user").Where(goqu.I("active").IsTrue()))
+//
+//s: The Dataset or CrudExec to scan, e.g. db.From("user") or db.From("user").Insert(user).Returning(goqu.Star())
+func All[T any](s Scannable) ([]T, error) {
+	var ret []T
+	if err := s.ScanStructs(&ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+//Scans a row of a Dataset or CrudExec into a struct of type T, false is returned if a row was not found. See
+//Dataset#ScanStruct
+//    user, found, err := goqu.One[User](db.From("user").Where(goqu.I("id").Eq(10)))
+//
+//s: The Dataset or CrudExec to scan
+func One[T any](s Scannable) (T, bool, error) {
+	var ret T
+	found, err := s.ScanStruct(&ret)
+	return ret, found, err
+}
+
+//Scans the rows of a single column of a Dataset or CrudExec into a slice of primitive values of type T, see
+//Dataset#ScanVals
+//    ids, err := goqu.Vals[int64](db.From("user").Select("id"))
+//
+//s: The Dataset or CrudExec to scan
+func Vals[T any](s Scannable) ([]T, error) {
+	var ret []T
+	if err := s.ScanVals(&ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+//Scans a row of a single column of a Dataset or CrudExec into a primitive value of type T, false is returned if a row
+//was not found. See Dataset#ScanVal
+//    id, found, err := goqu.Val[int64](db.From("user").Select("id").Where(goqu.I("email").Eq(email)))
+//
+//s: The Dataset or CrudExec to scan
+func Val[T any](s Scannable) (T, bool, error) {
+	var ret T
+	found, err := s.ScanVal(&ret)
+	return ret, found, err
+}
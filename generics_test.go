@@ -0,0 +1,98 @@
+//go:build go1.18
+// +build go1.18
+
+package goqu
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type dsTestGenericItem struct {
+	Id   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func (me *datasetTest) TestAll() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).FromCSVString("1,Test1\n2,Test2"))
+	sqlmock.ExpectQuery(`INSERT INTO "items" \("name"\) VALUES \('Test3'\) RETURNING "id", "name"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).FromCSVString("3,Test3"))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	db := New("mock", mDb)
+	items, err := All[dsTestGenericItem](db.From("items"))
+	assert.NoError(t, err)
+	assert.Equal(t, items, []dsTestGenericItem{{Id: 1, Name: "Test1"}, {Id: 2, Name: "Test2"}})
+
+	items, err = All[dsTestGenericItem](db.From("items").Returning("id", "name").Insert(map[string]interface{}{"name": "Test3"}))
+	assert.NoError(t, err)
+	assert.Equal(t, items, []dsTestGenericItem{{Id: 3, Name: "Test3"}})
+
+	items, err = All[dsTestGenericItem](db.From("items"))
+	assert.NoError(t, err)
+	assert.Len(t, items, 0)
+}
+
+func (me *datasetTest) TestOne() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).FromCSVString("1,Test1"))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	db := New("mock", mDb)
+	item, found, err := One[dsTestGenericItem](db.From("items"))
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, item, dsTestGenericItem{Id: 1, Name: "Test1"})
+
+	item, found, err = One[dsTestGenericItem](db.From("items"))
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, item, dsTestGenericItem{})
+
+	_, _, err = One[int64](db.From("items"))
+	assert.EqualError(t, err, "goqu: Type must be a pointer to a struct when calling ScanStruct")
+}
+
+func (me *datasetTest) TestVals() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT "id" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).FromCSVString("1\n2"))
+	sqlmock.ExpectQuery(`SELECT "name" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).FromCSVString("Test1"))
+	sqlmock.ExpectQuery(`SELECT "name" FROM "items" LIMIT 1`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"name"}))
+
+	db := New("mock", mDb)
+	ids, err := Vals[int64](db.From("items").Select("id"))
+	assert.NoError(t, err)
+	assert.Equal(t, ids, []int64{1, 2})
+
+	name, found, err := Val[string](db.From("items").Select("name"))
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, name, "Test1")
+
+	name, found, err = Val[string](db.From("items").Select("name"))
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, name, "")
+}
@@ -32,6 +32,34 @@ func (me *testOrderAdapter) SupportsOrderByOnUpdate() bool {
 	return true
 }
 
+type testNoExistsAdapter struct {
+	Adapter
+}
+
+func (me *testNoExistsAdapter) SupportsSelectExists() bool {
+	return false
+}
+
+type testNoConflictAdapter struct {
+	Adapter
+}
+
+func (me *testNoConflictAdapter) SupportsConflictTarget() bool {
+	return false
+}
+
+func (me *testNoConflictAdapter) SupportsConflictDoNothing() bool {
+	return false
+}
+
+func (me *testNoConflictAdapter) SupportsConflictUpdateWhere() bool {
+	return false
+}
+
+func (me *testNoConflictAdapter) SupportsConflictUpdateWithoutTarget() bool {
+	return true
+}
+
 func init() {
 	RegisterAdapter("mock", func(ds *Dataset) Adapter {
 		return NewDefaultAdapter(ds)
@@ -48,5 +76,18 @@ func init() {
 		adapter := NewDefaultAdapter(ds)
 		return &testOrderAdapter{adapter}
 	})
+	RegisterAdapter("no-exists", func(ds *Dataset) Adapter {
+		adapter := NewDefaultAdapter(ds)
+		return &testNoExistsAdapter{adapter}
+	})
+	RegisterAdapter("no-conflict", func(ds *Dataset) Adapter {
+		adapter := NewDefaultAdapter(ds)
+		return &testNoConflictAdapter{adapter}
+	})
+	RegisterAdapter("placeholder-limit", func(ds *Dataset) Adapter {
+		adapter := NewDefaultAdapter(ds).(*DefaultAdapter)
+		adapter.MaxPlaceholders = 5
+		return adapter
+	})
 
 }
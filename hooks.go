@@ -0,0 +1,134 @@
+package goqu
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type (
+	//Describes an operation executed by a Database or TxDatabase, it is passed to each Hook before and after the operation
+	//is executed.
+	QueryEvent struct {
+		//The operation being executed, one of EXEC, QUERY, QUERY ROW, PREPARE, COMMIT or ROLLBACK
+		Op string
+		//The SQL being executed, empty for COMMIT and ROLLBACK
+		Sql string
+		//The arguments for any placeholder parameters in the SQL
+		Args []interface{}
+		//Set to true if the operation was executed within a transaction
+		InTx bool
+		//How long the operation took, only set once the operation has been executed
+		Duration time.Duration
+		//The number of rows affected by an EXEC, -1 for all other operations or if the driver does not support it
+		RowsAffected int64
+		//The number of rows read from a QUERY executed by a Dataset or CrudExec, -1 for all other operations
+		RowsReturned int64
+		//The columns returned by a QUERY that were discarded because they do not map to a field of the struct being
+		//scanned into, see SCAN_LENIENT
+		UnmappedColumns []string
+		//The error returned by the operation, only set once the operation has been executed
+		Err error
+	}
+	//A Hook is called before and after each operation executed by a Database or TxDatabase. Hooks are added to a database
+	//with Use and are inherited by the transactions started from the database.
+	//
+	//When a QUERY is executed by a Dataset or CrudExec, After is called once all rows have been read and the Scanner is
+	//closed, so the Duration includes the time spent reading the rows.
+	//
+	//Hooks are run in the order they were added before the operation and in reverse order after it, like a middleware chain.
+	Hook interface {
+		//Called before the operation is executed, the returned context is passed to the next Hook and used to execute the
+		//operation, this allows a Hook to attach values (e.g. a tracing span) to the context.
+		Before(ctx context.Context, event *QueryEvent) context.Context
+		//Called after the operation has been executed with the Duration, RowsAffected and Err of the event set.
+		After(ctx context.Context, event *QueryEvent)
+	}
+	//A Hook that is only called after an operation has been executed
+	//    db.Use(goqu.HookFunc(func(ctx context.Context, event *goqu.QueryEvent) {
+	//        metrics.Observe(event.Op, event.Duration)
+	//    }))
+	HookFunc  func(ctx context.Context, event *QueryEvent)
+	hookChain []Hook
+)
+
+//Does nothing and returns the context as is
+func (me HookFunc) Before(ctx context.Context, event *QueryEvent) context.Context {
+	return ctx
+}
+
+//Calls the function with the executed event
+func (me HookFunc) After(ctx context.Context, event *QueryEvent) {
+	me(ctx, event)
+}
+
+func newQueryEvent(op, sql string, args []interface{}, inTx bool) *QueryEvent {
+	return &QueryEvent{Op: op, Sql: sql, Args: args, InTx: inTx, RowsAffected: -1, RowsReturned: -1}
+}
+
+//used internally to return a copy of the chain with the hooks appended, the copy ensures that hooks added to a transaction
+//are not added to the database the transaction was started from.
+func (me hookChain) with(hooks ...Hook) hookChain {
+	ret := make(hookChain, 0, len(me)+len(hooks))
+	ret = append(ret, me...)
+	return append(ret, hooks...)
+}
+
+//used internally to run an operation through the hooks of the chain, the operation is executed with the context returned
+//from the Before call of the last hook.
+func (me hookChain) run(ctx context.Context, event *QueryEvent, fn func(ctx context.Context) error) error {
+	ctx = me.before(ctx, event)
+	start := time.Now()
+	event.Err = fn(ctx)
+	event.Duration = time.Since(start)
+	me.after(ctx, event)
+	return event.Err
+}
+
+//used internally to run a query through the hooks of the chain, if the query succeeds the After hooks are called once the
+//returned Scanner is closed.
+func (me hookChain) scan(ctx context.Context, event *QueryEvent, fn func(ctx context.Context) (*sql.Rows, error)) (*Scanner, error) {
+	ctx = me.before(ctx, event)
+	start := time.Now()
+	rows, err := fn(ctx)
+	if err != nil {
+		event.Err = err
+		event.Duration = time.Since(start)
+		me.after(ctx, event)
+		return nil, err
+	}
+	scanner := newScanner(rows)
+	scanner.onClose = func(count int64, err error) {
+		event.RowsReturned = count
+		event.UnmappedColumns = scanner.unmapped
+		event.Err = err
+		event.Duration = time.Since(start)
+		me.after(ctx, event)
+	}
+	return scanner, nil
+}
+
+func (me hookChain) before(ctx context.Context, event *QueryEvent) context.Context {
+	for _, hook := range me {
+		ctx = hook.Before(ctx, event)
+	}
+	return ctx
+}
+
+func (me hookChain) after(ctx context.Context, event *QueryEvent) {
+	for i := len(me) - 1; i >= 0; i-- {
+		me[i].After(ctx, event)
+	}
+}
+
+//used internally to get the number of rows affected from the result of an EXEC
+func rowsAffected(result sql.Result) int64 {
+	if result == nil {
+		return -1
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
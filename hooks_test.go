@@ -0,0 +1,161 @@
+package goqu
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type hookTestContextKey string
+
+type hookTestRecorder struct {
+	name   string
+	calls  *[]string
+	events []QueryEvent
+}
+
+func (me *hookTestRecorder) Before(ctx context.Context, event *QueryEvent) context.Context {
+	*me.calls = append(*me.calls, fmt.Sprintf("%s before %s", me.name, event.Op))
+	return context.WithValue(ctx, hookTestContextKey(me.name), true)
+}
+
+func (me *hookTestRecorder) After(ctx context.Context, event *QueryEvent) {
+	*me.calls = append(*me.calls, fmt.Sprintf("%s after %s", me.name, event.Op))
+	me.events = append(me.events, *event)
+}
+
+type hooksTest struct {
+	suite.Suite
+}
+
+func (me *hooksTest) TestUse() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectExec(`UPDATE "items" SET "name"='Test1'`).
+		WithArgs().
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnError(NewGoquError("query error"))
+	sqlmock.ExpectPrepare(`SELECT \* FROM "items"`)
+
+	var calls []string
+	first, second := &hookTestRecorder{name: "first", calls: &calls}, &hookTestRecorder{name: "second", calls: &calls}
+	db := New("mock", mDb)
+	db.Use(first)
+	db.Use(second)
+	_, err = db.Exec(`UPDATE "items" SET "name"='Test1'`)
+	assert.NoError(t, err)
+	_, err = db.Query(`SELECT * FROM "items"`)
+	assert.EqualError(t, err, "goqu: query error")
+	_, err = db.Prepare(`SELECT * FROM "items"`)
+	assert.NoError(t, err)
+	assert.Equal(t, calls, []string{
+		"first before EXEC", "second before EXEC", "second after EXEC", "first after EXEC",
+		"first before QUERY", "second before QUERY", "second after QUERY", "first after QUERY",
+		"first before PREPARE", "second before PREPARE", "second after PREPARE", "first after PREPARE",
+	})
+
+	assert.Len(t, first.events, 3)
+	assert.Equal(t, first.events[0].Sql, `UPDATE "items" SET "name"='Test1'`)
+	assert.Equal(t, first.events[0].RowsAffected, int64(2))
+	assert.False(t, first.events[0].InTx)
+	assert.NoError(t, first.events[0].Err)
+	assert.Equal(t, first.events[1].RowsAffected, int64(-1))
+	assert.EqualError(t, first.events[1].Err, "goqu: query error")
+	assert.Equal(t, first.events[2].Sql, `SELECT * FROM "items"`)
+}
+
+func (me *hooksTest) TestUse_Context() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT "name" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).FromCSVString("Test1"))
+
+	var calls []string
+	var ctxValues []interface{}
+	db := New("mock", mDb)
+	db.Use(&hookTestRecorder{name: "first", calls: &calls}, HookFunc(func(ctx context.Context, event *QueryEvent) {
+		ctxValues = append(ctxValues, ctx.Value(hookTestContextKey("first")))
+	}))
+	var name string
+	found, err := db.From("items").Select("name").ScanVal(&name)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, ctxValues, []interface{}{true})
+}
+
+func (me *hooksTest) TestUse_Transaction() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectExec(`UPDATE "items" SET "name"='Test1'`).
+		WithArgs().
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlmock.ExpectCommit()
+	sqlmock.ExpectBegin()
+	sqlmock.ExpectRollback()
+
+	var calls []string
+	dbHook, txHook := &hookTestRecorder{name: "db", calls: &calls}, &hookTestRecorder{name: "tx", calls: &calls}
+	db := New("mock", mDb)
+	db.Use(dbHook)
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	tx.Use(txHook)
+	_, err = tx.Exec(`UPDATE "items" SET "name"='Test1'`)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, calls, []string{
+		"db before EXEC", "tx before EXEC", "tx after EXEC", "db after EXEC",
+		"db before COMMIT", "tx before COMMIT", "tx after COMMIT", "db after COMMIT",
+	})
+	assert.True(t, dbHook.events[0].InTx)
+	assert.Equal(t, dbHook.events[1].Sql, "")
+
+	calls = calls[0:0]
+	tx, err = db.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Rollback())
+	assert.Equal(t, calls, []string{"db before ROLLBACK", "db after ROLLBACK"})
+}
+
+func (me *hooksTest) TestUse_RowsReturned() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1\n211 Test Addr,Test2"))
+	sqlmock.ExpectQuery(`SELECT "name" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"name", "other"}).FromCSVString("Test1,Test2"))
+
+	var events []QueryEvent
+	db := New("mock", mDb)
+	db.Use(HookFunc(func(ctx context.Context, event *QueryEvent) {
+		events = append(events, *event)
+	}))
+	var items []testActionItem
+	assert.NoError(t, db.From("items").ScanStructs(&items))
+	var names []string
+	assert.Error(t, db.From("items").Select("name").ScanVals(&names))
+
+	assert.Len(t, events, 2)
+	assert.Equal(t, events[0].RowsReturned, int64(2))
+	assert.NoError(t, events[0].Err)
+	assert.Equal(t, events[1].RowsReturned, int64(1))
+	assert.Error(t, events[1].Err)
+}
+
+func TestHooksSuite(t *testing.T) {
+	suite.Run(t, new(hooksTest))
+}
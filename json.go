@@ -0,0 +1,61 @@
+package goqu
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+type (
+	//used internally to wrap the value of a struct field tagged with goqu:"json" so it is JSON encoded when inserting or
+	//updating a struct
+	jsonValue struct {
+		value interface{}
+	}
+	//used internally to decode a JSON column into a struct field tagged with goqu:"json"
+	jsonScanner struct {
+		dest reflect.Value
+	}
+)
+
+//used internally to check if a struct field is tagged with goqu:"json"
+func isJsonField(field reflect.StructField) bool {
+	return tagOptions(field.Tag.Get("goqu")).Contains("json")
+}
+
+//used internally to encode the value of a JSON field, nil maps, slices and pointers are encoded as NULL
+func (me jsonValue) encode() ([]byte, error) {
+	val := reflect.ValueOf(me.value)
+	switch val.Kind() {
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Map, reflect.Slice, reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return nil, nil
+		}
+	}
+	b, err := json.Marshal(me.value)
+	if err != nil {
+		return nil, NewGoquError("Unable to encode JSON value: %s", err.Error())
+	}
+	return b, nil
+}
+
+//Decodes a JSON column, the field is set to its zero value if the column is NULL
+func (me jsonScanner) Scan(src interface{}) error {
+	me.dest.Set(reflect.Zero(me.dest.Type()))
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return NewGoquError("Unable to decode %T into a JSON field", src)
+	}
+	if err := json.Unmarshal(b, me.dest.Addr().Interface()); err != nil {
+		return NewGoquError("Unable to decode JSON value: %s", err.Error())
+	}
+	return nil
+}
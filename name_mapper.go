@@ -0,0 +1,73 @@
+package goqu
+
+import (
+	"strings"
+	"unicode"
+)
+
+type (
+	//Maps the name of a struct field that does not have a db tag to a column name when scanning, inserting and updating
+	//structs. See Database#NameMapper
+	NameMapper interface {
+		//Returns the column name of the struct field
+		ColumnName(field string) string
+	}
+	snakeCaseMapper struct{}
+	lowerCaseMapper struct{}
+	exactMapper     struct{}
+	funcMapper      struct {
+		fn func(field string) string
+	}
+)
+
+//Returns a NameMapper that maps a field name to snake case (e.g. CreatedAt -> created_at, UserID -> user_id)
+func SnakeCaseMapper() NameMapper {
+	return snakeCaseMapper{}
+}
+
+//Returns a NameMapper that maps a field name to lower case (e.g. CreatedAt -> createdat)
+func LowerCaseMapper() NameMapper {
+	return lowerCaseMapper{}
+}
+
+//Returns a NameMapper that uses the field name as is (e.g. CreatedAt -> CreatedAt)
+func ExactMapper() NameMapper {
+	return exactMapper{}
+}
+
+//Returns a NameMapper that maps a field name using a function, the NameMapper should be created once and reused as the
+//columns of each struct are cached per NameMapper.
+//    db.NameMapper(goqu.NameMapperFunc(func(field string) string {
+//        return "col_" + strings.ToLower(field)
+//    }))
+func NameMapperFunc(fn func(field string) string) NameMapper {
+	return &funcMapper{fn: fn}
+}
+
+func (me snakeCaseMapper) ColumnName(field string) string {
+	runes := []rune(field)
+	var buf strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			//start a new word after a lower case letter or digit, or at the last upper case letter of an acronym
+			if i > 0 && runes[i-1] != '_' && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				buf.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+func (me lowerCaseMapper) ColumnName(field string) string {
+	return strings.ToLower(field)
+}
+
+func (me exactMapper) ColumnName(field string) string {
+	return field
+}
+
+func (me *funcMapper) ColumnName(field string) string {
+	return me.fn(field)
+}
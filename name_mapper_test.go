@@ -0,0 +1,49 @@
+package goqu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type nameMapperTest struct {
+	suite.Suite
+}
+
+func (me *nameMapperTest) TestSnakeCaseMapper() {
+	t := me.T()
+	mapper := SnakeCaseMapper()
+	assert.Equal(t, mapper.ColumnName("Name"), "name")
+	assert.Equal(t, mapper.ColumnName("CreatedAt"), "created_at")
+	assert.Equal(t, mapper.ColumnName("UserID"), "user_id")
+	assert.Equal(t, mapper.ColumnName("ID"), "id")
+	assert.Equal(t, mapper.ColumnName("HTTPServer"), "http_server")
+	assert.Equal(t, mapper.ColumnName("Address2"), "address2")
+	assert.Equal(t, mapper.ColumnName("Line2Text"), "line2_text")
+	assert.Equal(t, mapper.ColumnName("Already_Snake"), "already_snake")
+}
+
+func (me *nameMapperTest) TestLowerCaseMapper() {
+	t := me.T()
+	assert.Equal(t, LowerCaseMapper().ColumnName("CreatedAt"), "createdat")
+}
+
+func (me *nameMapperTest) TestExactMapper() {
+	t := me.T()
+	assert.Equal(t, ExactMapper().ColumnName("CreatedAt"), "CreatedAt")
+}
+
+func (me *nameMapperTest) TestNameMapperFunc() {
+	t := me.T()
+	mapper := NameMapperFunc(func(field string) string {
+		return "col_" + strings.ToLower(field)
+	})
+	assert.Equal(t, mapper.ColumnName("CreatedAt"), "col_createdat")
+	assert.NotEqual(t, mapper, NameMapperFunc(strings.ToLower))
+}
+
+func TestNameMapperSuite(t *testing.T) {
+	suite.Run(t, new(nameMapperTest))
+}
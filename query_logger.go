@@ -0,0 +1,65 @@
+package goqu
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+)
+
+//A Hook that logs each operation with how long it took, the rows returned or affected and the error if one occurred.
+//    db.Use(goqu.QueryLogger{Logger: log.New(os.Stdout, "", log.LstdFlags), SlowThreshold: 100 * time.Millisecond})
+//    //[goqu] QUERY [query:=`SELECT * FROM "items"` args:=[] duration:=152.3ms rows:=10]
+type QueryLogger struct {
+	//The logger to write to
+	Logger Logger
+	//Only operations that take at least this long are logged, if 0 every operation is logged
+	SlowThreshold time.Duration
+	//Set to true to omit the arguments of statements from the log, e.g. if they may contain sensitive data
+	RedactArgs bool
+}
+
+//Does nothing and returns the context as is
+func (me QueryLogger) Before(ctx context.Context, event *QueryEvent) context.Context {
+	return ctx
+}
+
+//Logs the operation if it took at least as long as the SlowThreshold
+func (me QueryLogger) After(ctx context.Context, event *QueryEvent) {
+	if me.Logger == nil || event.Duration < me.SlowThreshold {
+		return
+	}
+	me.Logger.Printf("%s", me.format(event))
+}
+
+//used internally to format an event into a log line
+func (me QueryLogger) format(event *QueryEvent) string {
+	var buf bytes.Buffer
+	if event.InTx {
+		buf.WriteString("[goqu - transaction] ")
+	} else {
+		buf.WriteString("[goqu] ")
+	}
+	buf.WriteString(event.Op)
+	buf.WriteString(" [")
+	if event.Sql != "" {
+		fmt.Fprintf(&buf, "query:=`%s` ", event.Sql)
+		if me.RedactArgs {
+			buf.WriteString("args:=REDACTED ")
+		} else {
+			fmt.Fprintf(&buf, "args:=%+v ", event.Args)
+		}
+	}
+	fmt.Fprintf(&buf, "duration:=%s", event.Duration)
+	if event.RowsReturned >= 0 {
+		fmt.Fprintf(&buf, " rows:=%d", event.RowsReturned)
+	}
+	if event.RowsAffected >= 0 {
+		fmt.Fprintf(&buf, " rows_affected:=%d", event.RowsAffected)
+	}
+	if event.Err != nil {
+		fmt.Fprintf(&buf, " error:=%q", event.Err.Error())
+	}
+	buf.WriteString("]")
+	return buf.String()
+}
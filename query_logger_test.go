@@ -0,0 +1,62 @@
+package goqu
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type queryLoggerTest struct {
+	suite.Suite
+}
+
+func (me *queryLoggerTest) TestAfter() {
+	t := me.T()
+	logger := new(dbTestMockLogger)
+	queryLogger := QueryLogger{Logger: logger}
+
+	event := newQueryEvent("QUERY", `SELECT * FROM "items" WHERE "id" = ?`, []interface{}{1}, false)
+	event.Duration = 10 * time.Millisecond
+	event.RowsReturned = 2
+	queryLogger.After(context.Background(), event)
+
+	event = newQueryEvent("EXEC", `UPDATE "items" SET "name"=?`, []interface{}{"Test"}, true)
+	event.Duration = 5 * time.Millisecond
+	event.RowsAffected = 3
+	event.Err = NewGoquError("exec error")
+	queryLogger.After(context.Background(), event)
+
+	event = newQueryEvent("COMMIT", "", nil, true)
+	event.Duration = time.Millisecond
+	queryLogger.After(context.Background(), event)
+
+	assert.Equal(t, logger.Messages, []string{
+		"[goqu] QUERY [query:=`SELECT * FROM \"items\" WHERE \"id\" = ?` args:=[1] duration:=10ms rows:=2]",
+		"[goqu - transaction] EXEC [query:=`UPDATE \"items\" SET \"name\"=?` args:=[Test] duration:=5ms rows_affected:=3 error:=\"goqu: exec error\"]",
+		"[goqu - transaction] COMMIT [duration:=1ms]",
+	})
+}
+
+func (me *queryLoggerTest) TestAfter_SlowThreshold() {
+	t := me.T()
+	logger := new(dbTestMockLogger)
+	queryLogger := QueryLogger{Logger: logger, SlowThreshold: 10 * time.Millisecond, RedactArgs: true}
+
+	event := newQueryEvent("QUERY", `SELECT * FROM "items" WHERE "id" = ?`, []interface{}{1}, false)
+	event.Duration = 9 * time.Millisecond
+	queryLogger.After(context.Background(), event)
+	assert.Len(t, logger.Messages, 0)
+
+	event.Duration = 10 * time.Millisecond
+	queryLogger.After(context.Background(), event)
+	assert.Equal(t, logger.Messages, []string{
+		"[goqu] QUERY [query:=`SELECT * FROM \"items\" WHERE \"id\" = ?` args:=REDACTED duration:=10ms]",
+	})
+}
+
+func TestQueryLoggerSuite(t *testing.T) {
+	suite.Run(t, new(queryLoggerTest))
+}
@@ -0,0 +1,36 @@
+package goqu
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+type (
+	//Chooses the replica a read is routed to when a Database is created with NewWithReplicas. See Database#ReplicaBalancer
+	ReplicaBalancer interface {
+		//Returns the index of the replica to use, n is the number of replicas and is always greater than 0.
+		Next(n int) int
+	}
+	roundRobinBalancer struct {
+		next uint64
+	}
+	randomBalancer struct{}
+)
+
+//Returns a ReplicaBalancer that routes reads to each replica in turn, this is the default balancer.
+func RoundRobinBalancer() ReplicaBalancer {
+	return &roundRobinBalancer{}
+}
+
+//Returns a ReplicaBalancer that routes each read to a random replica.
+func RandomBalancer() ReplicaBalancer {
+	return randomBalancer{}
+}
+
+func (me *roundRobinBalancer) Next(n int) int {
+	return int((atomic.AddUint64(&me.next, 1) - 1) % uint64(n))
+}
+
+func (me randomBalancer) Next(n int) int {
+	return rand.Intn(n)
+}
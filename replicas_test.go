@@ -0,0 +1,36 @@
+package goqu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type replicaBalancerTest struct {
+	suite.Suite
+}
+
+func (me *replicaBalancerTest) TestRoundRobinBalancer() {
+	t := me.T()
+	balancer := RoundRobinBalancer()
+	var chosen []int
+	for i := 0; i < 5; i++ {
+		chosen = append(chosen, balancer.Next(3))
+	}
+	assert.Equal(t, chosen, []int{0, 1, 2, 0, 1})
+	assert.Equal(t, balancer.Next(1), 0)
+}
+
+func (me *replicaBalancerTest) TestRandomBalancer() {
+	t := me.T()
+	balancer := RandomBalancer()
+	for i := 0; i < 20; i++ {
+		next := balancer.Next(3)
+		assert.True(t, next >= 0 && next < 3)
+	}
+}
+
+func TestReplicaBalancerSuite(t *testing.T) {
+	suite.Run(t, new(replicaBalancerTest))
+}
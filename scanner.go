@@ -0,0 +1,377 @@
+package goqu
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+)
+
+//database types, other than the CHAR, TEXT and CLOB variants, whose []byte values are converted to strings by ScanRecord
+var textual_types = map[string]bool{
+	"JSON":  true,
+	"JSONB": true,
+	"UUID":  true,
+	"ENUM":  true,
+	"SET":   true,
+	"NAME":  true,
+	"XML":   true,
+}
+
+//Controls how the columns returned by a query are matched to the fields of a struct when scanning, see Database#ScanMode
+//and Dataset#ScanMode
+type ScanMode int
+
+const (
+	//Returns an error if a column returned by the query does not map to a field of the struct
+	SCAN_DEFAULT ScanMode = iota + 1
+	//Discards the columns that do not map to a field of the struct, the discarded columns are reported to hooks in
+	//QueryEvent#UnmappedColumns
+	SCAN_LENIENT
+	//Returns an error if a column does not map to a field of the struct or a field of the struct is not returned by the query.
+	//Unexported fields and the fields of a pointer to a nested struct, which is left nil if its columns are not returned,
+	//are not required.
+	SCAN_STRICT
+)
+
+//A Scanner is used to iterate over the rows returned by a query one row at a time, scanning each row directly into the
+//destination without loading the whole result set into memory. A Scanner must be closed once you are done with it.
+//    scanner, err := db.From("items").Iter()
+//    if err != nil{
+//        panic(err.Error())
+//    }
+//    defer scanner.Close()
+//    for scanner.Next(){
+//        var item Item
+//        if err := scanner.ScanStruct(&item); err != nil{
+//            panic(err.Error())
+//        }
+//        //use your item
+//    }
+//    if err := scanner.Err(); err != nil{
+//        panic(err.Error())
+//    }
+type Scanner struct {
+	rows    *sql.Rows
+	columns []string
+	textual []bool
+	fields  *scanFields
+	mode    ScanMode
+	mapper  NameMapper
+	count   int64
+	err     error
+	onClose func(count int64, err error)
+	//wraps the errors returned by the rows in a DatabaseError, see newDatabaseError
+	classify func(err error) error
+	//the columns discarded by SCAN_LENIENT
+	unmapped []string
+}
+
+//used internally to remember the struct fields the columns of a Scanner are scanned into
+type scanFields struct {
+	structType reflect.Type
+	columns    []columnData
+	//the index paths of the pointers to nested structs
+	parents [][]int
+	//the position in parents of the pointer to a nested struct of each column, -1 if the column is not in one
+	parentOf []int
+}
+
+func newScanner(rows *sql.Rows) *Scanner {
+	return &Scanner{rows: rows}
+}
+
+//Prepares the next row to be scanned. Returns false when there are no more rows or an error occurred, see Err.
+func (me *Scanner) Next() bool {
+	if me.rows.Next() {
+		me.count++
+		return true
+	}
+	return false
+}
+
+//Returns the column names of the rows being scanned
+func (me *Scanner) Columns() ([]string, error) {
+	if me.columns == nil {
+		columns, err := me.rows.Columns()
+		if err != nil {
+			return nil, me.databaseError(err)
+		}
+		me.columns = columns
+	}
+	return me.columns, nil
+}
+
+//Scans the current row into a struct, each column is mapped to a field using the db tag of the field.
+//
+//i: A pointer to a struct
+func (me *Scanner) ScanStruct(i interface{}) error {
+	val := reflect.ValueOf(i)
+	if val.Kind() != reflect.Ptr || reflect.Indirect(val).Kind() != reflect.Struct {
+		return NewGoquError("Type must be a pointer to a struct when calling ScanStruct")
+	}
+	cm, err := getColumnMap(i, me.mapper)
+	if err != nil {
+		return err
+	}
+	return me.scanStruct(val.Elem(), cm)
+}
+
+//Scans the current row of a single column into a primitive value
+//
+//i: A pointer to a primitive value
+func (me *Scanner) ScanVal(i interface{}) error {
+	val := reflect.ValueOf(i)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return NewGoquError("Type must be a pointer when calling ScanVal")
+	}
+	if val := val.Elem(); isArrayType(val.Type()) {
+		//a slice is scanned from an array column (e.g. when calling ScanVals with a *[][]string)
+		i = arrayScanner{dest: val}
+	}
+	return me.setErr(me.databaseError(me.rows.Scan(i)))
+}
+
+//Scans the current row into a Record keyed by column name. If the driver returns a []byte for a column with a textual
+//database type (e.g. VARCHAR, TEXT, JSON) the value is converted to a string.
+func (me *Scanner) ScanRecord() (Record, error) {
+	columns, err := me.Columns()
+	if err != nil {
+		return nil, err
+	}
+	textual, err := me.textualColumns()
+	if err != nil {
+		return nil, err
+	}
+	scans := make([]interface{}, len(columns))
+	for i := range columns {
+		scans[i] = new(interface{})
+	}
+	if err := me.rows.Scan(scans...); err != nil {
+		return nil, me.setErr(me.databaseError(err))
+	}
+	record := make(Record, len(columns))
+	for i, col := range columns {
+		value := *(scans[i].(*interface{}))
+		if b, ok := value.([]byte); ok && textual[i] {
+			value = string(b)
+		}
+		record[col] = value
+	}
+	return record, nil
+}
+
+//Returns the error, if any, that was encountered while iterating over the rows. The error is a DatabaseError, see
+//Adapter#ClassifyError.
+func (me *Scanner) Err() error {
+	return me.databaseError(me.rows.Err())
+}
+
+//Closes the underlying rows, it is safe to call Close more than once.
+func (me *Scanner) Close() error {
+	if me.onClose != nil {
+		err := me.err
+		if err == nil {
+			err = me.Err()
+		}
+		onClose := me.onClose
+		me.onClose = nil
+		defer onClose(me.count, err)
+	}
+	return me.databaseError(me.rows.Close())
+}
+
+//used internally to wrap an error returned by the rows in a DatabaseError the same way the errors of the query are
+func (me *Scanner) databaseError(err error) error {
+	if err == nil || me.classify == nil {
+		return err
+	}
+	return me.classify(err)
+}
+
+//used internally to remember the first error encountered while scanning so it can be reported to hooks
+func (me *Scanner) setErr(err error) error {
+	if me.err == nil {
+		me.err = err
+	}
+	return err
+}
+
+//used internally to scan the columns of the current row into the values
+func (me *Scanner) scanVals(i ...interface{}) error {
+	return me.setErr(me.databaseError(me.rows.Scan(i...)))
+}
+
+//used internally to scan the current row directly into the fields of a struct
+func (me *Scanner) scanStruct(val reflect.Value, cm columnMap) error {
+	columns, err := me.Columns()
+	if err != nil {
+		return err
+	}
+	//the columns are the same for every row so the fields are only looked up once per struct type
+	if me.fields == nil || me.fields.structType != val.Type() {
+		fields, err := newScanFields(val.Type(), columns, cm, me.mode)
+		if err != nil {
+			return me.setErr(err)
+		}
+		me.fields = fields
+		for i, col := range columns {
+			if fields.columns[i].FieldIndex == nil && !containsString(me.unmapped, col) {
+				me.unmapped = append(me.unmapped, col)
+			}
+		}
+	}
+	scans := make([]interface{}, len(columns))
+	//the columns of a pointer to a nested struct are scanned into pointers so the struct can be left nil if they are all NULL
+	nullable := make([]reflect.Value, len(columns))
+	for i, data := range me.fields.columns {
+		if data.FieldIndex == nil {
+			//an unmapped column discarded by SCAN_LENIENT
+			scans[i] = new(interface{})
+			continue
+		}
+		var field reflect.Value
+		if data.ParentIndex == nil {
+			field = val.FieldByIndex(data.FieldIndex)
+		} else {
+			//a NULL column leaves the pointer nil
+			nullable[i] = reflect.New(reflect.PtrTo(data.GoType))
+			field = nullable[i].Elem()
+		}
+		switch {
+		case data.Json:
+			scans[i] = jsonScanner{dest: field}
+		case data.Array:
+			scans[i] = arrayScanner{dest: field}
+		default:
+			scans[i] = field.Addr().Interface()
+		}
+	}
+	if err := me.rows.Scan(scans...); err != nil {
+		return me.setErr(me.databaseError(err))
+	}
+	me.fields.setNested(val, nullable)
+	return nil
+}
+
+//used internally to find the struct fields of the columns, an unmapped column discarded by SCAN_LENIENT has a nil
+//FieldIndex
+func newScanFields(t reflect.Type, columns []string, cm columnMap, mode ScanMode) (*scanFields, error) {
+	fields := &scanFields{structType: t, columns: make([]columnData, len(columns)), parentOf: make([]int, len(columns))}
+	if mode == SCAN_STRICT {
+		for _, col := range cm.selectCols() {
+			if data := cm[col]; data.ParentIndex != nil || !isExportedField(t, data.FieldIndex) {
+				continue
+			}
+			if !containsString(columns, col) {
+				return nil, NewGoquError(`Unable to find corresponding column "%s" for field %s in the columns returned by query`, col, cm[col].FieldName)
+			}
+		}
+	}
+	for i, col := range columns {
+		fields.parentOf[i] = -1
+		data, ok := cm[col]
+		if !ok {
+			if mode == SCAN_LENIENT {
+				continue
+			}
+			return nil, NewGoquError(`Unable to find corresponding field to column "%s" returned by query`, col)
+		}
+		fields.columns[i] = data
+		if data.ParentIndex == nil {
+			continue
+		}
+		for p, index := range fields.parents {
+			if reflect.DeepEqual(index, data.ParentIndex) {
+				fields.parentOf[i] = p
+			}
+		}
+		if fields.parentOf[i] == -1 {
+			fields.parentOf[i] = len(fields.parents)
+			fields.parents = append(fields.parents, data.ParentIndex)
+		}
+	}
+	return fields, nil
+}
+
+//used internally to check if the field of a struct type at the index path can be set, the exported fields of an embedded
+//unexported struct can be set
+func isExportedField(t reflect.Type, index []int) bool {
+	for _, i := range index {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			return false
+		}
+		t = f.Type
+	}
+	return true
+}
+
+//used internally to set the pointers to nested structs from the scanned columns, a pointer is set to nil if all of its
+//columns are NULL
+func (me *scanFields) setNested(val reflect.Value, nullable []reflect.Value) {
+	if len(me.parents) == 0 {
+		return
+	}
+	notNull := make([]bool, len(me.parents))
+	for i, v := range nullable {
+		if v.IsValid() && !v.Elem().IsNil() {
+			notNull[me.parentOf[i]] = true
+		}
+	}
+	for p, index := range me.parents {
+		parent := fieldByIndexAlloc(val, index)
+		if notNull[p] {
+			parent.Set(reflect.New(parent.Type().Elem()))
+		} else {
+			parent.Set(reflect.Zero(parent.Type()))
+		}
+	}
+	for i, v := range nullable {
+		if v.IsValid() && notNull[me.parentOf[i]] && !v.Elem().IsNil() {
+			fieldByIndexAlloc(val, me.columns[i].FieldIndex).Set(v.Elem().Elem())
+		}
+	}
+}
+
+//used internally to check if a string is in a slice
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+//used internally to find the columns with a textual database type, if the driver does not report the database type of
+//a column it is not considered textual
+func (me *Scanner) textualColumns() ([]bool, error) {
+	if me.textual == nil {
+		types, err := me.rows.ColumnTypes()
+		if err != nil {
+			return nil, me.databaseError(err)
+		}
+		textual := make([]bool, len(types))
+		for i, t := range types {
+			textual[i] = isTextualType(t.DatabaseTypeName())
+		}
+		me.textual = textual
+	}
+	return me.textual, nil
+}
+
+//used internally to check if a database type name (e.g. VARCHAR(255), TEXT, JSONB) is textual
+func isTextualType(name string) bool {
+	name = strings.ToUpper(name)
+	if i := strings.IndexRune(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	switch {
+	case strings.Contains(name, "CHAR"), strings.Contains(name, "TEXT"), strings.Contains(name, "CLOB"):
+		return true
+	}
+	return textual_types[strings.TrimSpace(name)]
+}
@@ -0,0 +1,153 @@
+package goqu
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type scannerTest struct {
+	suite.Suite
+}
+
+func (me *scannerTest) TestScanStruct() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name", "phone_number", "age"}).FromCSVString("111 Test Addr,Test1,111-111-1111,20\n211 Test Addr,Test2,222-222-2222,30"))
+
+	db := New("mock", mDb)
+	scanner, err := db.From("items").Iter()
+	assert.NoError(t, err)
+	defer scanner.Close()
+
+	var items []testComposedCrudActionItem
+	for scanner.Next() {
+		var item testComposedCrudActionItem
+		assert.NoError(t, scanner.ScanStruct(&item))
+		items = append(items, item)
+	}
+	assert.NoError(t, scanner.Err())
+	assert.Len(t, items, 2)
+	assert.Equal(t, items[0].Address, "111 Test Addr")
+	assert.Equal(t, items[0].Name, "Test1")
+	assert.Equal(t, items[0].PhoneNumber, "111-111-1111")
+	assert.Equal(t, items[0].Age, int64(20))
+	assert.Equal(t, items[1].Address, "211 Test Addr")
+	assert.Equal(t, items[1].Name, "Test2")
+	assert.Equal(t, items[1].PhoneNumber, "222-222-2222")
+	assert.Equal(t, items[1].Age, int64(30))
+	assert.NoError(t, scanner.Close())
+}
+
+func (me *scannerTest) TestScanStruct_Errors() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT "test" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"test"}).FromCSVString("test1"))
+
+	db := New("mock", mDb)
+	scanner, err := db.From("items").Select("test").Iter()
+	assert.NoError(t, err)
+	defer scanner.Close()
+
+	assert.True(t, scanner.Next())
+	var item testCrudActionItem
+	assert.EqualError(t, scanner.ScanStruct(item), "goqu: Type must be a pointer to a struct when calling ScanStruct")
+	var items []testCrudActionItem
+	assert.EqualError(t, scanner.ScanStruct(&items), "goqu: Type must be a pointer to a struct when calling ScanStruct")
+	assert.EqualError(t, scanner.ScanStruct(&item), `goqu: Unable to find corresponding field to column "test" returned by query`)
+}
+
+func (me *scannerTest) TestScanVal() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT "id" FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).FromCSVString("1\n2\n3"))
+
+	db := New("mock", mDb)
+	scanner, err := db.From("items").Select("id").Iter()
+	assert.NoError(t, err)
+	defer scanner.Close()
+
+	var ids []int64
+	for scanner.Next() {
+		var id int64
+		assert.EqualError(t, scanner.ScanVal(id), "goqu: Type must be a pointer when calling ScanVal")
+		assert.EqualError(t, scanner.ScanVal((*int64)(nil)), "goqu: Type must be a pointer when calling ScanVal")
+		assert.NoError(t, scanner.ScanVal(&id))
+		ids = append(ids, id)
+	}
+	assert.NoError(t, scanner.Err())
+	assert.Equal(t, ids, []int64{1, 2, 3})
+}
+
+func (me *scannerTest) TestScanRecord() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT \* FROM "items"`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1\n211 Test Addr,Test2"))
+
+	db := New("mock", mDb)
+	scanner, err := db.From("items").Iter()
+	assert.NoError(t, err)
+	defer scanner.Close()
+
+	columns, err := scanner.Columns()
+	assert.NoError(t, err)
+	assert.Equal(t, columns, []string{"address", "name"})
+	var records []Record
+	for scanner.Next() {
+		record, err := scanner.ScanRecord()
+		assert.NoError(t, err)
+		records = append(records, record)
+	}
+	assert.NoError(t, scanner.Err())
+	assert.Equal(t, records, []Record{
+		{"address": []byte("111 Test Addr"), "name": []byte("Test1")},
+		{"address": []byte("211 Test Addr"), "name": []byte("Test2")},
+	})
+}
+
+func (me *scannerTest) TestIsTextualType() {
+	t := me.T()
+	for _, name := range []string{"VARCHAR", "varchar(255)", "CHAR", "BPCHAR", "NVARCHAR", "TEXT", "MEDIUMTEXT", "CITEXT", "CLOB", "JSON", "JSONB", "UUID", "ENUM"} {
+		assert.True(t, isTextualType(name), name)
+	}
+	for _, name := range []string{"", "INT", "DECIMAL(10, 2)", "BLOB", "BYTEA", "VARBINARY", "TIMESTAMP"} {
+		assert.False(t, isTextualType(name), name)
+	}
+}
+
+func (me *scannerTest) TestQueryError() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectQuery(`SELECT "id" FROM "items"`).
+		WithArgs().
+		WillReturnError(fmt.Errorf("query error"))
+
+	db := New("mock", mDb)
+	scanner, err := db.From("items").Select("id").Iter()
+	assert.EqualError(t, err, "query error")
+	assert.Nil(t, scanner)
+
+	scanner, err = newCrudExec(db, fmt.Errorf("crud exec error"), `SELECT "id" FROM "items"`).Iter()
+	assert.EqualError(t, err, "crud exec error")
+	assert.Nil(t, scanner)
+}
+
+func TestScannerSuite(t *testing.T) {
+	suite.Run(t, new(scannerTest))
+}
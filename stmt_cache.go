@@ -0,0 +1,117 @@
+package goqu
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+)
+
+type (
+	//used internally to hold prepared statements keyed by their sql, the least recently used statement is evicted and
+	//closed once the cache is full.
+	stmtCache struct {
+		mu      sync.Mutex
+		prepare func(ctx context.Context, query string) (*sql.Stmt, error)
+		size    int
+		lru     *list.List
+		entries map[string]*list.Element
+	}
+	stmtCacheEntry struct {
+		query   string
+		stmt    *sql.Stmt
+		refs    int
+		evicted bool
+	}
+)
+
+func newStmtCache(prepare func(ctx context.Context, query string) (*sql.Stmt, error), size int) *stmtCache {
+	return &stmtCache{prepare: prepare, size: size, lru: list.New(), entries: make(map[string]*list.Element)}
+}
+
+//Returns the cached statement for the query, preparing it if it is not cached yet. The returned function must be called
+//once the statement has been executed, a statement that is evicted while in use is only closed once it is released.
+//The statement is prepared without holding the lock so other queries are not blocked by the round trip.
+func (me *stmtCache) acquire(ctx context.Context, query string) (*sql.Stmt, func(), error) {
+	if stmt, release, ok := me.get(query); ok {
+		return stmt, release, nil
+	}
+	stmt, err := me.prepare(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	if elem, ok := me.entries[query]; ok {
+		//the query was prepared by another caller in the meantime
+		stmt.Close()
+		return me.use(elem)
+	}
+	entry := &stmtCacheEntry{query: query, stmt: stmt, refs: 1}
+	me.entries[query] = me.lru.PushFront(entry)
+	for me.lru.Len() > me.size {
+		me.evict(me.lru.Back())
+	}
+	return stmt, me.releaser(entry), nil
+}
+
+//Returns the cached statement for the query without preparing it, false is returned if the query is not cached. The
+//returned function must be called once the statement has been executed.
+func (me *stmtCache) get(query string) (*sql.Stmt, func(), bool) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	if elem, ok := me.entries[query]; ok {
+		stmt, release, _ := me.use(elem)
+		return stmt, release, true
+	}
+	return nil, nil, false
+}
+
+//used internally to mark a cached statement as in use, must be called while holding the lock
+func (me *stmtCache) use(elem *list.Element) (*sql.Stmt, func(), error) {
+	me.lru.MoveToFront(elem)
+	entry := elem.Value.(*stmtCacheEntry)
+	entry.refs++
+	return entry.stmt, me.releaser(entry), nil
+}
+
+//Returns the number of statements currently cached
+func (me *stmtCache) len() int {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	return me.lru.Len()
+}
+
+//Evicts all statements from the cache, statements that are not in use are closed immediately.
+func (me *stmtCache) clear() {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	for me.lru.Len() > 0 {
+		me.evict(me.lru.Back())
+	}
+}
+
+//used internally to remove an element from the cache, must be called while holding the lock
+func (me *stmtCache) evict(elem *list.Element) {
+	entry := me.lru.Remove(elem).(*stmtCacheEntry)
+	delete(me.entries, entry.query)
+	entry.evicted = true
+	if entry.refs == 0 {
+		entry.stmt.Close()
+	}
+}
+
+//used internally to create the function that releases a statement returned from acquire
+func (me *stmtCache) releaser(entry *stmtCacheEntry) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			me.mu.Lock()
+			defer me.mu.Unlock()
+			entry.refs--
+			if entry.evicted && entry.refs == 0 {
+				entry.stmt.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package goqu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type stmtCacheTest struct {
+	suite.Suite
+}
+
+func (me *stmtCacheTest) TestAcquire() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectPrepare(`SELECT \* FROM "items"`)
+	sqlmock.ExpectPrepare(`SELECT \* FROM "items"`).WillReturnError(NewGoquError("prepare error"))
+
+	cache := newStmtCache(mDb.PrepareContext, 2)
+	stmt, release, err := cache.acquire(context.Background(), `SELECT * FROM "items"`)
+	assert.NoError(t, err)
+	release()
+	cached, release, err := cache.acquire(context.Background(), `SELECT * FROM "items"`)
+	assert.NoError(t, err)
+	release()
+	assert.True(t, stmt == cached)
+	assert.Equal(t, cache.len(), 1)
+
+	cache.clear()
+	assert.Equal(t, cache.len(), 0)
+	_, _, err = cache.acquire(context.Background(), `SELECT * FROM "items"`)
+	assert.EqualError(t, err, "goqu: prepare error")
+	assert.Equal(t, cache.len(), 0)
+}
+
+func (me *stmtCacheTest) TestEvict() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectPrepare(`SELECT \* FROM "items"`)
+	sqlmock.ExpectPrepare(`SELECT \* FROM "users"`)
+	sqlmock.ExpectPrepare(`SELECT \* FROM "orders"`)
+
+	cache := newStmtCache(mDb.PrepareContext, 1)
+	items, releaseItems, err := cache.acquire(context.Background(), `SELECT * FROM "items"`)
+	assert.NoError(t, err)
+	users, releaseUsers, err := cache.acquire(context.Background(), `SELECT * FROM "users"`)
+	assert.NoError(t, err)
+	releaseUsers()
+	assert.Equal(t, cache.len(), 1)
+
+	//the evicted statement is still in use so it is not closed until it is released
+	assert.Equal(t, cache.entries[`SELECT * FROM "users"`].Value.(*stmtCacheEntry).stmt, users)
+	_, err = items.Exec()
+	assert.NotEqual(t, err.Error(), "sql: statement is closed")
+	releaseItems()
+	releaseItems()
+	_, err = items.Exec()
+	assert.EqualError(t, err, "sql: statement is closed")
+
+	_, release, err := cache.acquire(context.Background(), `SELECT * FROM "orders"`)
+	assert.NoError(t, err)
+	release()
+	_, err = users.Exec()
+	assert.EqualError(t, err, "sql: statement is closed")
+}
+
+func (me *stmtCacheTest) TestGet() {
+	t := me.T()
+	mDb, err := sqlmock.New()
+	assert.NoError(t, err)
+	sqlmock.ExpectPrepare(`SELECT \* FROM "items"`)
+
+	cache := newStmtCache(mDb.PrepareContext, 2)
+	_, _, ok := cache.get(`SELECT * FROM "items"`)
+	assert.False(t, ok)
+	assert.Equal(t, cache.len(), 0)
+	stmt, release, err := cache.acquire(context.Background(), `SELECT * FROM "items"`)
+	assert.NoError(t, err)
+	release()
+	cached, release, ok := cache.get(`SELECT * FROM "items"`)
+	assert.True(t, ok)
+	assert.True(t, stmt == cached)
+	release()
+}
+
+func TestStmtCacheSuite(t *testing.T) {
+	suite.Run(t, new(stmtCacheTest))
+}